@@ -0,0 +1,25 @@
+package cmd_check
+
+import (
+	"fmt"
+	"gf-blog/app/library/document"
+	"os"
+)
+
+// 检查文档中的失效链接：站内文档链接、锚点、图片、静态文件以及菜单项，存在失效链接时以非0状态码退出。
+// 用法: gf-blog check
+func Run() {
+	lib_document.SetupVersions()
+	broken := lib_document.CheckLinks()
+	for _, item := range broken {
+		if item.Type == lib_document.BROKEN_MENU {
+			fmt.Printf("%s: [%s] %s (%s)\n", item.Path, item.Type, item.Link, item.Text)
+		} else {
+			fmt.Printf("%s: [%s] %s\n", item.Path, item.Type, item.Link)
+		}
+	}
+	fmt.Printf("%d broken links found\n", len(broken))
+	if len(broken) > 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,138 @@
+package cmd_export
+
+import (
+	"encoding/json"
+	"gf-blog/app/controller/document"
+	"gf-blog/app/library/document"
+	"github.com/gogf/gf/g"
+	"github.com/gogf/gf/g/os/gcmd"
+	"github.com/gogf/gf/g/os/gfile"
+	"github.com/gogf/gf/g/os/glog"
+	"github.com/gogf/gf/g/text/gregex"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// 默认的导出目录
+	defaultOutPath = "dist"
+	// 需要转换为静态链接的HTML属性
+	linkPattern = `(href|src|action|data-href|data-index)="(/[^"]*)"`
+)
+
+// 导出静态站点：渲染所有文档页面并转换站内链接，复制public静态资源，生成客户端检索使用的检索索引文件。
+// 用法: gf-blog export --out=dist [--base=/docs]，其中base为部署到子路径时的链接前缀
+func Run() {
+	out  := getOption("out", defaultOutPath)
+	base := strings.TrimRight(getOption("base", ""), "/")
+	// 静态页面不需要实时刷新，也不包含修订历史、订阅源等动态页面的链接
+	g.View().Assign("devMode", false)
+	g.View().Assign("exportMode", true)
+	lib_document.SetupVersions()
+
+	count := 0
+	for _, path := range lib_document.GetDocumentPaths() {
+		content, found, err := ctl_document.RenderDocument(path, g.Config().GetString("setting.url"))
+		if err != nil {
+			glog.Cat("export").Printfln("render %s error: %v", path, err)
+			continue
+		}
+		if !found {
+			continue
+		}
+		if err := writeFile(out, path+".html", rewriteLinks(string(content), base)); err != nil {
+			glog.Fatal(err)
+		}
+		count++
+	}
+	// 检索页面，以及各版本的检索索引文件
+	content, err := ctl_document.RenderSearchPage("", "/search-index.json")
+	if err != nil {
+		glog.Fatal(err)
+	}
+	if err := writeFile(out, "search.html", rewriteLinks(string(content), base)); err != nil {
+		glog.Fatal(err)
+	}
+	for _, v := range lib_document.GetVersions() {
+		b, err := json.Marshal(lib_document.ExportSearchIndex(v.Name))
+		if err != nil {
+			glog.Fatal(err)
+		}
+		name := "search-index.json"
+		if v.Name != "" {
+			name = v.Name + "/" + name
+		}
+		if err := writeFile(out, name, string(b)); err != nil {
+			glog.Fatal(err)
+		}
+	}
+	if err := copyDir("public", out); err != nil {
+		glog.Fatal(err)
+	}
+	glog.Cat("export").Printfln("exported %d documents to %s", count, out)
+}
+
+// 获得命令行选项，同时支持 --name=value 及 --name value 两种格式
+func getOption(name string, def string) string {
+	if value := gcmd.Option.Get(name); value != "" {
+		return value
+	}
+	values := gcmd.Value.GetAll()
+	for i, value := range values {
+		if (value == "--"+name || value == "-"+name) && i+1 < len(values) {
+			return values[i+1]
+		}
+	}
+	return def
+}
+
+// 将站内链接转换为静态站点的链接：文档路径添加.html后缀，检索页面转换为search.html，并添加部署路径前缀
+func rewriteLinks(content string, base string) string {
+	content, _ = gregex.ReplaceStringFunc(linkPattern, content, func(s string) string {
+		match, _ := gregex.MatchString(linkPattern, s)
+		link     := match[2]
+		if strings.HasPrefix(link, "//") {
+			return s
+		}
+		suffix := ""
+		if i := strings.IndexAny(link, "?#"); i >= 0 {
+			link, suffix = link[:i], link[i:]
+		}
+		switch {
+		case link == "/":
+			link = "/index.html"
+		case link == "/search":
+			link = "/search.html"
+		case lib_document.IsVersionRoot(link):
+			link = strings.TrimRight(link, "/") + "/index.html"
+		case !lib_document.IsStaticPath(link):
+			link = strings.TrimRight(link, "/") + ".html"
+		}
+		return match[1] + `="` + base + link + suffix + `"`
+	})
+	return content
+}
+
+// 写入导出文件，path为使用"/"分隔的相对路径
+func writeFile(out string, path string, content string) error {
+	return gfile.PutContents(filepath.Join(out, filepath.FromSlash(path)), content)
+}
+
+// 递归复制目录
+func copyDir(src string, dst string) error {
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		if info.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+		return gfile.Copy(path, target)
+	})
+}
@@ -0,0 +1,68 @@
+package cmd_user
+
+import (
+	"fmt"
+	"gf-blog/app/model/user"
+	"github.com/gogf/gf/g/os/gcmd"
+	"os"
+)
+
+// 创建用户，用户已存在时更新其角色、昵称、邮箱及密码(未指定的选项保持不变)，用于初始化管理员账号。
+// 用法: gf-blog user --name=admin --password=secret [--role=admin] [--nickname=] [--email=]
+func Run() {
+	name     := getOption("name", "")
+	password := getOption("password", "")
+	if name == "" {
+		fail(fmt.Errorf("--name is required"))
+	}
+	u, err := model_user.GetByUsername(name)
+	if err != nil {
+		fail(err)
+	}
+	if u == nil {
+		u = &model_user.User{Username: name}
+		setFields(u)
+		if _, err := model_user.Create(u, password); err != nil {
+			fail(err)
+		}
+		fmt.Printf("user %s (%s) created\n", u.Username, u.Role)
+		return
+	}
+	setFields(u)
+	if err := model_user.Update(u); err != nil {
+		fail(err)
+	}
+	if password != "" {
+		if err := model_user.SetPassword(u.Id, password); err != nil {
+			fail(err)
+		}
+	}
+	fmt.Printf("user %s (%s) updated\n", u.Username, u.Role)
+}
+
+// 使用命令行选项设置用户字段，未指定的选项保持不变
+func setFields(u *model_user.User) {
+	u.Role     = getOption("role", u.Role)
+	u.Nickname = getOption("nickname", u.Nickname)
+	u.Email    = getOption("email", u.Email)
+}
+
+// 输出错误信息并以非0状态码退出
+func fail(err error) {
+	fmt.Println(err)
+	os.Exit(1)
+}
+
+// 获得命令行选项，支持 --name=value 及 --name value 两种格式
+func getOption(name string, def string) string {
+	if value := gcmd.Option.Get(name); value != "" {
+		return value
+	}
+	values := gcmd.Value.GetAll()
+	for i, value := range values {
+		if (value == "--"+name || value == "-"+name) && i+1 < len(values) {
+			return values[i+1]
+		}
+	}
+	return def
+}
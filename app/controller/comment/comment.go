@@ -0,0 +1,227 @@
+package ctl_comment
+
+import (
+	"fmt"
+	"gf-blog/app/library/auth"
+	"gf-blog/app/library/document"
+	"gf-blog/app/model"
+	"gf-blog/app/model/comment"
+	"gf-blog/app/model/post"
+	"github.com/gogf/gf/g"
+	"github.com/gogf/gf/g/net/ghttp"
+	"github.com/gogf/gf/g/os/gcache"
+	"github.com/gogf/gf/g/os/glog"
+	"github.com/gogf/gf/g/util/gconv"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+const (
+	// 管理接口评论列表每页数量
+	adminPageSize = 20
+	// 批量获得评论数量时最多允许的页面数量
+	maxCountPaths = 100
+	// 同一IP在计数有效时间内最多允许发表的评论数量
+	postMaxPerIp = 5
+	// 同一页面在计数有效时间内最多允许发表的评论数量(防止通过多个IP灌水)
+	postMaxPerPath = 30
+	// 评论发表计数的有效时间(毫秒)
+	postCountExpire = 10 * 60 * 1000
+)
+
+// 获得页面已通过审核的评论树及评论数量
+func List(r *ghttp.Request) {
+	path := normalizePath(r.Get("path"))
+	comments, err := model_comment.GetThread(path)
+	if err != nil {
+		writeJson(r, http.StatusInternalServerError, 0, err.Error(), nil)
+		return
+	}
+	count, _ := model_comment.GetCount(path)
+	writeJson(r, http.StatusOK, 1, "", g.Map{
+		"path":     path,
+		"count":    count,
+		"comments": comments,
+	})
+}
+
+// 批量获得多个页面的评论数量，paths参数使用逗号分隔，最多maxCountPaths个页面
+func Count(r *ghttp.Request) {
+	paths := make([]string, 0)
+	for _, path := range strings.Split(r.Get("paths"), ",") {
+		if path = strings.TrimSpace(path); path != "" {
+			paths = append(paths, path)
+		}
+	}
+	if len(paths) > maxCountPaths {
+		writeJson(r, http.StatusBadRequest, 0, fmt.Sprintf("too many paths, at most %d are allowed", maxCountPaths), nil)
+		return
+	}
+	counts, err := model_comment.GetCounts(paths)
+	if err != nil {
+		writeJson(r, http.StatusInternalServerError, 0, err.Error(), nil)
+		return
+	}
+	writeJson(r, http.StatusOK, 1, "", counts)
+}
+
+// 发表评论，同一IP及同一页面的发表频率受到限制，ajax请求时返回JSON数据，否则跳转回评论所在页面
+func Post(r *ghttp.Request) {
+	if !g.Config().GetBool("comment.enabled") {
+		writeJson(r, http.StatusForbidden, 0, "comment is disabled", nil)
+		return
+	}
+	path := normalizePath(r.GetPostString("path"))
+	if !commentable(path) {
+		writeJson(r, http.StatusNotFound, 0, "page not found", nil)
+		return
+	}
+	ipKey   := "comment_post_ip_" + lib_auth.ClientIp(r)
+	pathKey := "comment_post_path_" + path
+	if gconv.Int(gcache.Get(ipKey)) >= postMaxPerIp || gconv.Int(gcache.Get(pathKey)) >= postMaxPerPath {
+		writeJson(r, http.StatusTooManyRequests, 0, "too many comments, please try again later", nil)
+		return
+	}
+	// 校验失败的提交同样计数，避免通过大量非法提交探测
+	addCount(ipKey)
+	addCount(pathKey)
+	c := &model_comment.Comment{
+		Path:     path,
+		ParentId: r.GetPostInt("parent"),
+		Author:   r.GetPostString("author"),
+		Email:    r.GetPostString("email"),
+		Markdown: r.GetPostString("content"),
+		Ip:       lib_auth.ClientIp(r),
+	}
+	if _, err := model_comment.Create(c); err != nil {
+		status := http.StatusBadRequest
+		if err == model.ErrNoDatabase {
+			status = http.StatusServiceUnavailable
+		}
+		glog.Cat("comment").Printfln("comment from %s rejected: %v", c.Ip, err)
+		writeJson(r, status, 0, err.Error(), nil)
+		return
+	}
+	if r.IsAjaxRequest() {
+		writeJson(r, http.StatusOK, 1, "", g.Map{
+			"id":     c.Id,
+			"status": c.Status,
+		})
+		return
+	}
+	r.Response.RedirectTo(path + "?comment=" + c.Status + "#comments")
+}
+
+// 增加评论发表计数，每次增加时重新计算有效时间
+func addCount(key string) {
+	gcache.Set(key, gconv.Int(gcache.Get(key))+1, postCountExpire)
+}
+
+// 管理接口：分页获得评论列表，可以通过status参数筛选状态(默认为待审核)
+func AdminList(r *ghttp.Request) {
+	if !lib_auth.Check(r, lib_auth.PERM_MODERATE) {
+		return
+	}
+	status := r.Get("status", model_comment.STATUS_PENDING)
+	if status == "all" {
+		status = ""
+	}
+	page := r.GetInt("page", 1)
+	if page < 1 {
+		page = 1
+	}
+	list, total, err := model_comment.GetList(status, page, adminPageSize)
+	if err != nil {
+		writeJson(r, http.StatusInternalServerError, 0, err.Error(), nil)
+		return
+	}
+	writeJson(r, http.StatusOK, 1, "", g.Map{
+		"status":   status,
+		"total":    total,
+		"page":     page,
+		"size":     adminPageSize,
+		"comments": list,
+	})
+}
+
+// 管理接口：变更评论状态(审核通过、标记为垃圾评论、删除、恢复)
+func AdminModerate(r *ghttp.Request) {
+	if !lib_auth.Check(r, lib_auth.PERM_MODERATE) {
+		return
+	}
+	c, err := model_comment.Moderate(r.GetInt("id"), r.Get("status"))
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch err {
+		case model_comment.ErrNotFound:
+			status = http.StatusNotFound
+		case model_comment.ErrInvalidTransition:
+			status = http.StatusBadRequest
+		}
+		writeJson(r, status, 0, err.Error(), nil)
+		return
+	}
+	glog.Cat("comment").Printfln("comment %d moderated to %s by %s", c.Id, c.Status, lib_auth.ClientIp(r))
+	writeJson(r, http.StatusOK, 1, "", c)
+}
+
+// 向页面模板变量中添加评论相关的数据，用于在文档及文章页面显示评论
+func AddParams(r *ghttp.Request, params g.Map, path string) {
+	if !g.Config().GetBool("comment.enabled") {
+		return
+	}
+	path = normalizePath(path)
+	comments, err := model_comment.GetThread(path)
+	if err != nil {
+		if err != model.ErrNoDatabase {
+			glog.Cat("comment").Printfln("load comments of %s error: %v", path, err)
+		}
+		return
+	}
+	params["commentEnabled"] = true
+	params["commentPath"]    = path
+	params["comments"]       = comments
+	params["commentCount"]   = countComments(comments)
+	params["commentReply"]   = r.GetInt("reply")
+	params["commentNotice"]  = r.Get("comment")
+}
+
+// 评论树中的评论总数(包含回复)
+func countComments(comments []*model_comment.Comment) int {
+	count := len(comments)
+	for _, c := range comments {
+		count += countComments(c.Replies)
+	}
+	return count
+}
+
+// 判断页面是否存在并且允许评论(可访问的文档或者已发布的博客文章)
+func commentable(path string) bool {
+	if strings.HasPrefix(path, "/blog/") {
+		p, err := model_post.GetBySlug(strings.TrimPrefix(path, "/blog/"))
+		return err == nil && p != nil && p.IsVisible()
+	}
+	return lib_document.IsDocumentPath(path)
+}
+
+// 规范化页面路径，去掉查询参数及锚点，清理"."及".."等相对路径，包含前导"/"
+func normalizePath(uri string) string {
+	if u, err := url.Parse(uri); err == nil {
+		uri = u.Path
+	}
+	return "/" + strings.Trim(path.Clean("/"+uri), "/")
+}
+
+// 输出JSON数据
+func writeJson(r *ghttp.Request, status int, code int, msg string, data interface{}) {
+	if status != http.StatusOK {
+		r.Response.WriteHeader(status)
+	}
+	r.Response.WriteJson(g.Map{
+		"code": code,
+		"msg":  msg,
+		"data": data,
+	})
+}
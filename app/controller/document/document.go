@@ -1,25 +1,214 @@
 package ctl_document
 
 import (
+	"gf-blog/app/controller/comment"
+	"gf-blog/app/library/auth"
 	"gf-blog/app/library/document"
 	"github.com/gogf/gf/g"
 	"github.com/gogf/gf/g/net/ghttp"
+	"github.com/gogf/gf/g/text/gstr"
+	"strings"
 )
 
-func Index(r *ghttp.Request)  {
-	if r.IsAjaxRequest(){
+// 文档页面，根据URI解析为对应的markdown文档
+func Index(r *ghttp.Request) {
+	path := getDocPath(r)
+	// 非法路径，或者静态文件请求(表示Web Server未找到该文件)，本接口不做处理
+	if path == "" || lib_document.IsStaticPath(path) {
+		r.Response.WriteStatus(404)
+		return
+	}
+	// 根据用户的语言偏好跳转到对应语言的文档
+	if target := getLanguageRedirect(r, path); target != "" {
+		r.Response.Header().Set("Location", "/"+target)
+		r.Response.WriteHeader(302)
+		return
+	}
+	page := loadDocPage(path)
+	if page.markdown == "" {
+		// 文档不存在时，尝试通过文档别名访问
+		if target := lib_document.GetPathByAlias(path); target != "" {
+			r.Response.Header().Set("Location", "/"+target)
+			r.Response.WriteHeader(301)
+			return
+		}
+	}
+	page.parse()
+	// 如果是ajax请求，那么直接返回文档内容
+	if r.IsAjaxRequest() {
+		var history *lib_document.History
+		if page.content != "" {
+			history = lib_document.GetHistory(path)
+		}
+		serveMarkdownAjax(r, g.Map{
+			"path":     page.path,
+			"meta":     page.meta,
+			"markdown": page.markdown,
+			"html":     page.content,
+			"toc":      page.toc,
+			"prev":     page.prev,
+			"next":     page.next,
+			"lang":     lib_document.GetPathLanguage(path).Code,
+			"fallback": page.fallback,
+			"history":  history,
+		})
+		return
+	}
+	params := page.params(getBaseUrl(r))
+	params["canEdit"] = lib_auth.Can(r, lib_auth.PERM_EDIT_DOC)
+	if page.content == "" {
+		r.Response.WriteHeader(404)
+	} else {
+		ctl_comment.AddParams(r, params, path)
+	}
+	r.Response.WriteTpl("layout.html", params)
+}
 
+// 渲染指定路径的文档页面，文档不存在时found返回false，用于静态导出等非HTTP请求的场景
+func RenderDocument(path string, baseUrl string) (content []byte, found bool, err error) {
+	page := loadDocPage(path)
+	page.parse()
+	if page.content == "" {
+		return nil, false, nil
 	}
+	content, err = g.View().Parse("layout.html", page.params(baseUrl))
+	return content, true, err
 }
 
+// 文档页面数据
+type docPage struct {
+	path     string                  // 文档路径
+	meta     *lib_document.Meta      // 文档元数据
+	markdown string                  // markdown内容(不包含front matter)
+	content  string                  // 解析后的html内容
+	toc      []*lib_document.TocItem // 文档目录
+	prev     *lib_document.PageLink  // 上一篇
+	next     *lib_document.PageLink  // 下一篇
+	fallback bool                    // 是否为未翻译文档回退显示的默认语言内容
+}
 
+// 加载文档页面数据，文档未翻译时回退加载默认语言的文档
+func loadDocPage(path string) *docPage {
+	page := &docPage{path: path}
+	page.meta, page.markdown = lib_document.GetMarkdownWithMeta(path)
+	if page.markdown == "" {
+		if target := lib_document.GetFallbackPath(path); target != "" {
+			page.meta, page.markdown = lib_document.GetMarkdownWithMeta(target)
+			page.fallback            = page.markdown != ""
+		}
+	}
+	return page
+}
 
+// 解析文档内容，生成html、目录以及翻页链接
+func (page *docPage) parse() {
+	// 草稿文档只在开发模式下可见
+	if page.meta.Draft && !g.Config().GetBool("setting.devmode") {
+		page.markdown = ""
+	}
+	page.content, page.toc = lib_document.ParseMarkdownWithToc(page.markdown)
+	page.prev, page.next   = lib_document.GetPrevNext(page.path)
+}
+
+// 文档页面的模板变量，文档不存在时不查询标题及版本历史，避免任意路径的请求执行git命令及占用缓存
+func (page *docPage) params(baseUrl string) g.Map {
+	path  := page.path
+	title := ""
+	var history *lib_document.History
+	if page.content != "" {
+		history = lib_document.GetHistory(path)
+		if title = lib_document.GetTitleByPath(path); title == "" {
+			title = page.meta.Title
+		}
+	}
+	if title == "" {
+		title = "404 NOT FOUND"
+	}
+	if suffix := g.Config().GetString("document.title"); suffix != "" {
+		title += " - " + suffix
+	}
+	return g.Map{
+		"title"        : title,
+		"description"  : page.meta.Description,
+		"path"         : path,
+		"meta"         : page.meta,
+		"mainTpl"      : "document/index.html",
+		"menu"         : lib_document.GetMenu(path).Nodes,
+		"menuHtml"     : lib_document.RenderMenu(lib_document.GetMenu(path).Nodes, path),
+		"versions"     : lib_document.GetVersionLinks(path),
+		"lang"         : lib_document.GetPathLanguage(path).Code,
+		"languages"    : lib_document.GetLanguageLinks(path),
+		"fallback"     : page.fallback,
+		"baseUrl"      : baseUrl,
+		"breadcrumb"   : lib_document.GetBreadcrumb(path),
+		"mdMarkdown"   : page.content,
+		"toc"          : page.toc,
+		"tocHtml"      : lib_document.RenderToc(page.toc),
+		"prev"         : page.prev,
+		"next"         : page.next,
+		"history"      : history,
+	}
+}
 
 // 处理ajax请求
-func serveMarkdownAjax(r *ghttp.Request) {
+func serveMarkdownAjax(r *ghttp.Request, data g.Map) {
 	r.Response.WriteJson(g.Map{
 		"code": 1,
 		"msg":  "",
-		"data": lib_document.GetMarkdown(r.Get("path", "index")),
+		"data": data,
 	})
-}
\ No newline at end of file
+}
+
+// 获得请求的文档路径，默认为index，非法路径返回空字符串
+func getDocPath(r *ghttp.Request) string {
+	path := strings.Trim(r.Get("path"), "/")
+	if path == "" {
+		path = "index"
+	}
+	// 防止通过相对路径访问文档目录以外的文件
+	if gstr.Contains(path, "..") {
+		return ""
+	}
+	return path
+}
+
+// 获得语言跳转的目标文档路径，无需跳转时返回空字符串。
+// 语言优先级：URL路径前缀 > lang参数(同时写入cookie) > cookie > Accept-Language
+func getLanguageRedirect(r *ghttp.Request, path string) string {
+	if len(lib_document.GetLanguages()) < 2 {
+		return ""
+	}
+	// 通过语言切换链接访问时，记住用户选择的语言
+	if code := r.Get("lang"); code != "" {
+		if lang := lib_document.GetLanguage(code); lang != nil {
+			r.Cookie.Set("lang", lang.Code)
+		}
+		return ""
+	}
+	// 只有默认语言(不带语言前缀)的路径才会根据用户偏好跳转
+	if lib_document.GetPathLanguage(path) != lib_document.GetLanguages()[0] {
+		return ""
+	}
+	// 跳转及不跳转的响应都取决于语言偏好，共享缓存需要按照这些请求头区分缓存
+	r.Response.Header().Set("Vary", "Accept-Language, Cookie")
+	lang := lib_document.GetLanguage(r.Cookie.Get("lang"))
+	if lang == nil {
+		lang = lib_document.MatchLanguage(r.Header.Get("Accept-Language"))
+	}
+	if lang == nil || lang == lib_document.GetLanguages()[0] {
+		return ""
+	}
+	return lib_document.GetLanguagePath(path, lang.Code)
+}
+
+// 获得站点的访问地址，优先使用setting.url配置，例如: https://goframe.org
+func getBaseUrl(r *ghttp.Request) string {
+	if url := g.Config().GetString("setting.url"); url != "" {
+		return strings.TrimRight(url, "/")
+	}
+	scheme := "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host
+}
@@ -0,0 +1,24 @@
+package ctl_document
+
+import (
+	"gf-blog/app/library/auth"
+	"gf-blog/app/library/document"
+	"github.com/gogf/gf/g"
+	"github.com/gogf/gf/g/net/ghttp"
+)
+
+// 管理接口：检查文档中的失效链接
+func Check(r *ghttp.Request) {
+	if !lib_auth.Check(r, lib_auth.PERM_MANAGE) {
+		return
+	}
+	broken := lib_document.CheckLinks()
+	r.Response.WriteJson(g.Map{
+		"code": 1,
+		"msg":  "",
+		"data": g.Map{
+			"total":  len(broken),
+			"broken": broken,
+		},
+	})
+}
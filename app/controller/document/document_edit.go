@@ -0,0 +1,111 @@
+package ctl_document
+
+import (
+	"gf-blog/app/library/auth"
+	"gf-blog/app/library/document"
+	"github.com/gogf/gf/g"
+	"github.com/gogf/gf/g/net/ghttp"
+	"net/http"
+	"strings"
+)
+
+// 文档编辑页面，加载文档的markdown源文件及其内容哈希，文档不存在时作为新文档编辑
+func Editor(r *ghttp.Request) {
+	if !lib_auth.Check(r, lib_auth.PERM_EDIT_DOC) {
+		return
+	}
+	path := getDocPath(r)
+	content, hash, exists, err := lib_document.GetSource(path)
+	if err != nil {
+		r.Response.WriteStatus(http.StatusNotFound)
+		return
+	}
+	r.Response.WriteTpl("layout.html", editorParams(r, path, content, hash, exists, ""))
+}
+
+// 预览接口，将提交的markdown内容(可以包含front matter)按照文档页面相同的规则解析为html
+func EditorPreview(r *ghttp.Request) {
+	if !lib_auth.Check(r, lib_auth.PERM_EDIT_DOC) {
+		return
+	}
+	meta, markdown := lib_document.ParseFrontMatter(r.GetPostString("content"))
+	html, toc      := lib_document.ParseMarkdownWithToc(markdown)
+	r.Response.WriteJson(g.Map{
+		"code": 1,
+		"msg":  "",
+		"data": g.Map{
+			"meta": meta,
+			"html": html,
+			"toc":  toc,
+		},
+	})
+}
+
+// 保存文档，hash为加载时的内容哈希，文档在加载之后被修改时返回409状态(ajax请求)或者重新渲染编辑页面。
+// 开启editor.commit配置时同时提交到文档版本库，提交失败时文档已保存，返回错误信息
+func EditorSave(r *ghttp.Request) {
+	if !lib_auth.Check(r, lib_auth.PERM_EDIT_DOC) {
+		return
+	}
+	path    := strings.Trim(r.GetPostString("path"), "/")
+	content := r.GetPostString("content")
+	var editor *lib_document.Editor
+	if u := lib_auth.GetUser(r); u != nil {
+		editor = &lib_document.Editor{Name: u.DisplayName(), Email: u.Email}
+	}
+	hash, err := lib_document.SaveSource(path, content, r.GetPostString("hash"), editor, r.GetPostString("message"))
+	// 保存失败时hash为空，文档已保存但提交失败时返回保存后的内容哈希及错误信息
+	if err != nil && hash == "" {
+		if r.IsAjaxRequest() {
+			r.Response.WriteHeader(getSaveErrorStatus(err))
+			r.Response.WriteJson(g.Map{
+				"code": 0,
+				"msg":  err.Error(),
+				"data": nil,
+			})
+			return
+		}
+		r.Response.WriteHeader(getSaveErrorStatus(err))
+		r.Response.WriteTpl("layout.html", editorParams(r, path, content, r.GetPostString("hash"), true, err.Error()))
+		return
+	}
+	if r.IsAjaxRequest() {
+		msg := ""
+		if err != nil {
+			msg = err.Error()
+		}
+		r.Response.WriteJson(g.Map{
+			"code": 1,
+			"msg":  msg,
+			"data": g.Map{
+				"path": path,
+				"hash": hash,
+			},
+		})
+		return
+	}
+	r.Response.RedirectTo("/" + path)
+}
+
+// 保存失败时的HTTP状态码
+func getSaveErrorStatus(err error) int {
+	switch err {
+	case lib_document.ErrConflict:
+		return http.StatusConflict
+	case lib_document.ErrInvalidEditPath:
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// 文档编辑页面的模板变量
+func editorParams(r *ghttp.Request, path string, content string, hash string, exists bool, msg string) g.Map {
+	params := historyParams(path, "编辑: ")
+	params["mainTpl"]   = "document/editor.html"
+	params["content"]   = content
+	params["hash"]      = hash
+	params["exists"]    = exists
+	params["error"]     = msg
+	params["csrfToken"] = lib_auth.CsrfToken(r)
+	return params
+}
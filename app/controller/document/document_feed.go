@@ -0,0 +1,78 @@
+package ctl_document
+
+import (
+	"gf-blog/app/library/document"
+	"gf-blog/app/library/feed"
+	"github.com/gogf/gf/g"
+	"github.com/gogf/gf/g/net/ghttp"
+	"github.com/gogf/gf/g/text/gregex"
+	"strings"
+)
+
+const (
+	// 订阅源默认的条目数量
+	defaultFeedLimit = 20
+)
+
+// RSS 2.0 订阅源，section参数为目录前缀，例如: /rss/net/ghttp
+func Rss(r *ghttp.Request) {
+	content, err := buildFeed(r, "rss").Rss()
+	writeXml(r, "application/rss+xml", content, err)
+}
+
+// Atom 订阅源，section参数为目录前缀，例如: /atom/net/ghttp
+func Atom(r *ghttp.Request) {
+	content, err := buildFeed(r, "atom").Atom()
+	writeXml(r, "application/atom+xml", content, err)
+}
+
+// 根据最近更新的文档构建订阅源，所有链接均为绝对URL
+func buildFeed(r *ghttp.Request, kind string) *lib_feed.Feed {
+	baseUrl := getBaseUrl(r)
+	section := strings.Trim(r.Get("section"), "/")
+	limit   := g.Config().GetInt("feed.limit")
+	if limit <= 0 {
+		limit = defaultFeedLimit
+	}
+	f := &lib_feed.Feed{
+		Title:       g.Config().GetString("document.title"),
+		Link:        baseUrl + "/",
+		FeedLink:    baseUrl + "/" + kind + ".xml",
+		Description: g.Config().GetString("feed.description"),
+		Items:       make([]*lib_feed.Item, 0),
+	}
+	if section != "" {
+		if title := lib_document.GetTitleByPath(section); title != "" {
+			f.Title = title + " - " + f.Title
+		}
+		f.Link     = baseUrl + "/" + lib_document.EscapePath(section)
+		f.FeedLink = baseUrl + "/" + kind + "/" + lib_document.EscapePath(section)
+	}
+	for _, doc := range lib_document.GetRecentDocuments(section, limit) {
+		f.Items = append(f.Items, &lib_feed.Item{
+			Title:     doc.Title,
+			Link:      baseUrl + "/" + lib_document.EscapePath(doc.Path),
+			Author:    doc.Author,
+			Summary:   absoluteLinks(doc.Summary, baseUrl),
+			Published: doc.Date,
+			Updated:   doc.Updated,
+		})
+	}
+	return f
+}
+
+// 将html中以"/"开头的站内链接转换为绝对URL
+func absoluteLinks(content string, baseUrl string) string {
+	content, _ = gregex.ReplaceString(`(src|href)="/([^/"][^"]*)?"`, `$1="`+baseUrl+`/$2"`, content)
+	return content
+}
+
+// 输出XML内容
+func writeXml(r *ghttp.Request, contentType string, content []byte, err error) {
+	if err != nil {
+		r.Response.WriteStatus(500, err.Error())
+		return
+	}
+	r.Response.Header().Set("Content-Type", contentType+"; charset=utf-8")
+	r.Response.Write(content)
+}
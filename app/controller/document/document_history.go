@@ -0,0 +1,177 @@
+package ctl_document
+
+import (
+	"gf-blog/app/library/document"
+	"github.com/gogf/gf/g"
+	"github.com/gogf/gf/g/net/ghttp"
+	"github.com/gogf/gf/g/util/gpage"
+	"strings"
+)
+
+const (
+	// 修订历史每页数量
+	historyPageSize = 20
+)
+
+// 差异内容中的一行
+type diffLine struct {
+	Class string // 行类型: add/del/hunk/meta/ctx
+	Text  string // 行内容
+}
+
+// 文档修订历史，包含最后修改时间、贡献者及分页的修订版本列表
+func History(r *ghttp.Request) {
+	path := getDocPath(r)
+	if path == "" || lib_document.GetMarkdown(path) == "" {
+		r.Response.WriteStatus(404)
+		return
+	}
+	page        := r.GetInt("page", 1)
+	history     := lib_document.GetHistory(path)
+	list, total := lib_document.GetRevisions(path, page, historyPageSize)
+	if r.IsAjaxRequest() {
+		r.Response.WriteJson(g.Map{
+			"code": 1,
+			"msg":  "",
+			"data": g.Map{
+				"path":         path,
+				"updated":      history.Updated,
+				"author":       history.Author,
+				"contributors": history.Contributors,
+				"revisions":    list,
+				"total":        total,
+				"page":         page,
+				"size":         historyPageSize,
+			},
+		})
+		return
+	}
+	params := historyParams(path, "修订历史: ")
+	params["mainTpl"]   = "document/history.html"
+	params["history"]   = history
+	params["revisions"] = list
+	params["total"]     = total
+	params["pager"]     = gpage.New(total, historyPageSize, page, r.URL.String()).GetContent(1)
+	r.Response.WriteTpl("layout.html", params)
+}
+
+// 查看文档的历史修订版本
+func Revision(r *ghttp.Request) {
+	path := getDocPath(r)
+	hash := r.Get("hash")
+	if path == "" || lib_document.GetMarkdown(path) == "" {
+		r.Response.WriteStatus(404)
+		return
+	}
+	content, err := lib_document.GetRevisionMarkdown(path, hash)
+	if err != nil {
+		r.Response.WriteStatus(404)
+		return
+	}
+	page := &docPage{path: path}
+	page.meta, page.markdown = lib_document.ParseFrontMatter(content)
+	page.parse()
+	if r.IsAjaxRequest() {
+		serveMarkdownAjax(r, g.Map{
+			"path":     page.path,
+			"revision": hash,
+			"meta":     page.meta,
+			"markdown": page.markdown,
+			"html":     page.content,
+			"toc":      page.toc,
+		})
+		return
+	}
+	params := page.params(getBaseUrl(r))
+	params["revision"] = hash
+	r.Response.WriteTpl("layout.html", params)
+}
+
+// 文档两个修订版本之间的差异，to参数为空时与最新的修订版本比较
+func Diff(r *ghttp.Request) {
+	path := getDocPath(r)
+	from := r.Get("from")
+	to   := r.Get("to")
+	if path == "" || lib_document.GetMarkdown(path) == "" {
+		r.Response.WriteStatus(404)
+		return
+	}
+	if to == "" {
+		if history := lib_document.GetHistory(path); len(history.Revisions) > 0 {
+			to = history.Revisions[0].Hash
+		}
+	}
+	diff, err := lib_document.GetDiff(path, from, to)
+	if err != nil {
+		r.Response.WriteStatus(400)
+		return
+	}
+	if r.IsAjaxRequest() {
+		r.Response.WriteJson(g.Map{
+			"code": 1,
+			"msg":  "",
+			"data": g.Map{
+				"path": path,
+				"from": from,
+				"to":   to,
+				"diff": diff,
+			},
+		})
+		return
+	}
+	if r.Get("format") == "raw" {
+		r.Response.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		r.Response.Write(diff)
+		return
+	}
+	params := historyParams(path, "修订对比: ")
+	params["mainTpl"] = "document/diff.html"
+	params["from"]    = from
+	params["to"]      = to
+	params["lines"]   = parseDiffLines(diff)
+	r.Response.WriteTpl("layout.html", params)
+}
+
+// 修订历史相关页面的公共模板变量
+func historyParams(path string, prefix string) g.Map {
+	title := lib_document.GetTitleByPath(path)
+	if title == "" {
+		title = lib_document.GetMeta(path).Title
+	}
+	title = prefix + title
+	if suffix := g.Config().GetString("document.title"); suffix != "" {
+		title += " - " + suffix
+	}
+	return g.Map{
+		"title"        : title,
+		"path"         : path,
+		"menu"         : lib_document.GetMenu(path).Nodes,
+		"menuHtml"     : lib_document.RenderMenu(lib_document.GetMenu(path).Nodes, path),
+		"versions"     : lib_document.GetVersionLinks(path),
+		"lang"         : lib_document.GetPathLanguage(path).Code,
+		"languages"    : lib_document.GetLanguageLinks(path),
+		"breadcrumb"   : lib_document.GetBreadcrumb(path),
+	}
+}
+
+// 将统一格式的差异内容按行解析，用于页面按照行类型高亮显示
+func parseDiffLines(diff string) []diffLine {
+	lines := make([]diffLine, 0)
+	for _, text := range strings.Split(strings.TrimRight(diff, "\n"), "\n") {
+		class := "ctx"
+		switch {
+		case strings.HasPrefix(text, "+++"), strings.HasPrefix(text, "---"),
+			strings.HasPrefix(text, "diff "), strings.HasPrefix(text, "index "),
+			strings.HasPrefix(text, "similarity "), strings.HasPrefix(text, "rename "):
+			class = "meta"
+		case strings.HasPrefix(text, "@@"):
+			class = "hunk"
+		case strings.HasPrefix(text, "+"):
+			class = "add"
+		case strings.HasPrefix(text, "-"):
+			class = "del"
+		}
+		lines = append(lines, diffLine{Class: class, Text: text})
+	}
+	return lines
+}
@@ -0,0 +1,63 @@
+package ctl_document
+
+import (
+	"gf-blog/app/library/auth"
+	"gf-blog/app/library/document"
+	"gf-blog/app/library/webhook"
+	"github.com/gogf/gf/g"
+	"github.com/gogf/gf/g/net/ghttp"
+	"github.com/gogf/gf/g/os/glog"
+	"net/http"
+)
+
+// 文档版本库webhook，接收GitHub/Gitee/GitLab的推送事件并更新文档
+func Hook(r *ghttp.Request) {
+	push, err := lib_webhook.Parse(r.Header, r.GetRaw(), g.Config().GetString("hook.secret"))
+	if err != nil {
+		glog.Cat("doc-hook").Printfln("doc hook rejected from %s: %v", lib_auth.ClientIp(r), err)
+		status := http.StatusBadRequest
+		if err == lib_webhook.ErrInvalidSignature || err == lib_webhook.ErrEmptySecret {
+			status = http.StatusUnauthorized
+		}
+		r.Response.WriteHeader(status)
+		r.Response.WriteJson(g.Map{
+			"code": 0,
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	if !push.IsPush() {
+		r.Response.WriteJson(g.Map{
+			"code": 1,
+			"msg":  "ignored event: " + push.Event,
+			"data": push,
+		})
+		return
+	}
+	if !lib_document.IsTrackedRef(push.Ref) {
+		glog.Cat("doc-hook").Printfln("doc hook ignored, %s pushed to %s", push.Pusher, push.Ref)
+		r.Response.WriteJson(g.Map{
+			"code": 1,
+			"msg":  "ignored ref: " + push.Ref,
+			"data": push,
+		})
+		return
+	}
+	glog.Cat("doc-hook").Printfln("doc hook received from %s, %s pushed %s to %s", push.Provider, push.Pusher, push.After, push.Ref)
+	output, err := lib_document.UpdateDocGit()
+	if err != nil {
+		r.Response.WriteHeader(http.StatusInternalServerError)
+		r.Response.WriteJson(g.Map{
+			"code": 0,
+			"msg":  err.Error(),
+			"data": push,
+		})
+		return
+	}
+	r.Response.WriteJson(g.Map{
+		"code": 1,
+		"msg":  output,
+		"data": push,
+	})
+}
@@ -0,0 +1,30 @@
+package ctl_document
+
+import (
+	"gf-blog/app/library/livereload"
+	"github.com/gogf/gf/g"
+	"github.com/gogf/gf/g/net/ghttp"
+)
+
+// 开发模式下的文档实时刷新WebSocket连接，文档变更时向客户端推送changed事件
+func LiveReload(r *ghttp.Request) {
+	if !g.Config().GetBool("setting.devmode") {
+		r.Response.WriteStatus(404)
+		return
+	}
+	ws, err := r.WebSocket()
+	if err != nil {
+		return
+	}
+	client := lib_livereload.Register(r.Get("path"), ws)
+	defer func() {
+		lib_livereload.Unregister(client)
+		ws.Close()
+	}()
+	// 客户端不需要发送消息，读取循环用于检测连接断开
+	for {
+		if _, _, err := ws.ReadMessage(); err != nil {
+			return
+		}
+	}
+}
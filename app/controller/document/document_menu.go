@@ -0,0 +1,20 @@
+package ctl_document
+
+import (
+	"gf-blog/app/library/document"
+	"github.com/gogf/gf/g"
+	"github.com/gogf/gf/g/net/ghttp"
+)
+
+// 导航菜单接口，返回path参数所属版本的完整菜单树以及该文档的面包屑
+func Menu(r *ghttp.Request) {
+	path := r.Get("path")
+	r.Response.WriteJson(g.Map{
+		"code": 1,
+		"msg":  "",
+		"data": g.Map{
+			"menu":       lib_document.GetMenu(path).Nodes,
+			"breadcrumb": lib_document.GetBreadcrumb(path),
+		},
+	})
+}
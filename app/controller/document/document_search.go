@@ -0,0 +1,80 @@
+package ctl_document
+
+import (
+	"gf-blog/app/library/document"
+	"github.com/gogf/gf/g"
+	"github.com/gogf/gf/g/net/ghttp"
+	"github.com/gogf/gf/g/util/gpage"
+)
+
+const (
+	// 检索结果每页数量
+	searchPageSize = 10
+)
+
+// 文档检索，ajax请求时返回JSON数据，否则渲染检索结果页面
+func Search(r *ghttp.Request) {
+	query   := r.Get("key")
+	version := r.Get("version")
+	page    := r.GetInt("page", 1)
+	results := lib_document.SearchDocument(version, query)
+	if lang := r.Get("lang"); lang != "" {
+		results = lib_document.FilterByLanguage(results, lang)
+	}
+	total   := len(results)
+	if page < 1 {
+		page = 1
+	}
+	start := (page - 1) * searchPageSize
+	end   := start + searchPageSize
+	if start > total {
+		start = total
+	}
+	if end > total {
+		end = total
+	}
+	hits := lib_document.GetSearchHits(version, query, results[start:end])
+	if r.IsAjaxRequest() {
+		r.Response.WriteJson(g.Map{
+			"code": 1,
+			"msg":  "",
+			"data": g.Map{
+				"key":     query,
+				"version": version,
+				"total":   total,
+				"page":    page,
+				"size":    searchPageSize,
+				"hits":    hits,
+			},
+		})
+		return
+	}
+	params := searchParams(version, query)
+	params["total"] = total
+	params["hits"]  = hits
+	params["pager"] = gpage.New(total, searchPageSize, page, r.URL.String()).GetContent(1)
+	r.Response.WriteTpl("layout.html", params)
+}
+
+// 渲染静态站点使用的检索页面，检索在客户端根据导出的检索索引文件执行
+func RenderSearchPage(version string, indexUrl string) ([]byte, error) {
+	params := searchParams(version, "")
+	params["searchIndex"] = indexUrl
+	return g.View().Parse("layout.html", params)
+}
+
+// 检索页面的公共模板变量
+func searchParams(version string, query string) g.Map {
+	title := "搜索: " + query
+	if suffix := g.Config().GetString("document.title"); suffix != "" {
+		title += " - " + suffix
+	}
+	return g.Map{
+		"title"        : title,
+		"mainTpl"      : "document/search.html",
+		"menu"         : lib_document.GetMenu(version).Nodes,
+		"menuHtml"     : lib_document.RenderMenu(lib_document.GetMenu(version).Nodes, ""),
+		"key"          : query,
+		"version"      : version,
+	}
+}
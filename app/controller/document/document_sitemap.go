@@ -0,0 +1,80 @@
+package ctl_document
+
+import (
+	"fmt"
+	"gf-blog/app/library/document"
+	"gf-blog/app/library/sitemap"
+	"github.com/gogf/gf/g"
+	"github.com/gogf/gf/g/net/ghttp"
+	"strings"
+)
+
+// sitemap，文档数量超过单个sitemap的URL上限时输出sitemap索引，并拆分为/sitemap-1.xml、/sitemap-2.xml...
+func Sitemap(r *ghttp.Request) {
+	baseUrl := getBaseUrl(r)
+	groups  := lib_sitemap.Split(getSitemapUrls(baseUrl))
+	if len(groups) <= 1 {
+		urls := make([]lib_sitemap.Url, 0)
+		if len(groups) == 1 {
+			urls = groups[0]
+		}
+		content, err := lib_sitemap.Render(urls)
+		writeXml(r, "application/xml", content, err)
+		return
+	}
+	sitemaps := make([]lib_sitemap.Sitemap, len(groups))
+	for i, urls := range groups {
+		sitemaps[i] = lib_sitemap.Sitemap{
+			Loc:     fmt.Sprintf("%s/sitemap-%d.xml", baseUrl, i+1),
+			LastMod: lib_sitemap.LastMod(urls),
+		}
+	}
+	content, err := lib_sitemap.RenderIndex(sitemaps)
+	writeXml(r, "application/xml", content, err)
+}
+
+// sitemap索引中拆分后的sitemap文件，page从1开始
+func SitemapPage(r *ghttp.Request) {
+	page   := r.GetInt("page")
+	groups := lib_sitemap.Split(getSitemapUrls(getBaseUrl(r)))
+	if page < 1 || page > len(groups) {
+		r.Response.WriteStatus(404)
+		return
+	}
+	content, err := lib_sitemap.Render(groups[page-1])
+	writeXml(r, "application/xml", content, err)
+}
+
+// robots.txt，配置了robots.content时直接输出配置内容
+func Robots(r *ghttp.Request) {
+	r.Response.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if content := g.Config().GetString("robots.content"); content != "" {
+		r.Response.Write(content)
+		return
+	}
+	lines := []string{"User-agent: *"}
+	for _, path := range g.Config().GetStrings("robots.disallow") {
+		lines = append(lines, "Disallow: "+path)
+	}
+	if len(lines) == 1 {
+		lines = append(lines, "Disallow:")
+	}
+	lines = append(lines, "", "Sitemap: "+getBaseUrl(r)+"/sitemap.xml")
+	r.Response.Write(strings.Join(lines, "\n") + "\n")
+}
+
+// 获得所有文档的sitemap URL列表，文档路径进行URL编码，最后修改时间来自git提交记录或者文件修改时间
+func getSitemapUrls(baseUrl string) []lib_sitemap.Url {
+	paths := lib_document.GetDocumentPaths()
+	urls  := make([]lib_sitemap.Url, len(paths))
+	for i, path := range paths {
+		urls[i] = lib_sitemap.Url{
+			Loc:     baseUrl + "/" + lib_document.EscapePath(path),
+			LastMod: lib_document.GetUpdatedTime(path),
+		}
+		if path == "index" {
+			urls[i].Loc = baseUrl + "/"
+		}
+	}
+	return urls
+}
@@ -0,0 +1,108 @@
+package ctl_post
+
+import (
+	"gf-blog/app/controller/comment"
+	"gf-blog/app/library/document"
+	"gf-blog/app/model/post"
+	"gf-blog/app/model/taxonomy"
+	"github.com/gogf/gf/g"
+	"github.com/gogf/gf/g/net/ghttp"
+	"github.com/gogf/gf/g/os/glog"
+	"github.com/gogf/gf/g/util/gpage"
+	"net/http"
+)
+
+const (
+	// 文章列表每页数量
+	listPageSize = 10
+)
+
+// 文章列表，ajax请求时返回JSON数据，否则渲染文章列表页面
+func List(r *ghttp.Request) {
+	page := r.GetInt("page", 1)
+	if page < 1 {
+		page = 1
+	}
+	posts, total, err := model_post.GetPublishedList(page, listPageSize)
+	if err != nil {
+		writeError(r, err)
+		return
+	}
+	if r.IsAjaxRequest() {
+		r.Response.WriteJson(g.Map{
+			"code": 1,
+			"msg":  "",
+			"data": g.Map{
+				"total": total,
+				"page":  page,
+				"size":  listPageSize,
+				"posts": posts,
+			},
+		})
+		return
+	}
+	params := getParams("博客")
+	params["mainTpl"] = "post/list.html"
+	params["posts"]   = posts
+	params["total"]   = total
+	params["pager"]   = gpage.New(total, listPageSize, page, r.URL.String()).GetContent(1)
+	r.Response.WriteTpl("layout.html", params)
+}
+
+// 文章详情，只显示已发布且到达发布时间的文章
+func Detail(r *ghttp.Request) {
+	post, err := model_post.GetBySlug(r.Get("slug"))
+	if err != nil {
+		writeError(r, err)
+		return
+	}
+	if post == nil || !post.IsVisible() {
+		r.Response.WriteStatus(http.StatusNotFound)
+		return
+	}
+	if r.IsAjaxRequest() {
+		r.Response.WriteJson(g.Map{
+			"code": 1,
+			"msg":  "",
+			"data": post,
+		})
+		return
+	}
+	params := getParams(post.Title)
+	params["mainTpl"]    = "post/detail.html"
+	params["post"]       = post
+	params["tags"]       = getTerms(post.Tags, model_taxonomy.TagUrl)
+	params["categories"] = getTerms(post.Categories, model_taxonomy.CategoryUrl)
+	// 文章html在保存时渲染，模板中直接输出
+	params["content"]    = post.Html
+	ctl_comment.AddParams(r, params, post.Url())
+	r.Response.WriteTpl("layout.html", params)
+}
+
+// 文章页面的公共模板变量，侧边栏显示文档的导航菜单及标签云
+func getParams(title string) g.Map {
+	if suffix := g.Config().GetString("document.title"); suffix != "" {
+		title += " - " + suffix
+	}
+	return g.Map{
+		"title"        : title,
+		"menu"         : lib_document.GetMenu("").Nodes,
+		"menuHtml"     : lib_document.RenderMenu(lib_document.GetMenu("").Nodes, ""),
+		"tagCloud"     : model_taxonomy.GetTagCloud(g.Config().GetInt("taxonomy.cloudSize")),
+	}
+}
+
+// 将标签或者分类名称列表转换为带有访问地址的项目列表
+func getTerms(names []string, getUrl func(string) string) []*model_taxonomy.Term {
+	terms := make([]*model_taxonomy.Term, 0, len(names))
+	for _, name := range names {
+		terms = append(terms, &model_taxonomy.Term{Name: name, Path: name, Url: getUrl(name)})
+	}
+	return terms
+}
+
+// 数据库错误时返回500状态
+func writeError(r *ghttp.Request, err error) {
+	glog.Cat("post").Printfln("post query error: %v", err)
+	r.Response.WriteStatus(http.StatusInternalServerError)
+}
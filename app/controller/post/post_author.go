@@ -0,0 +1,164 @@
+package ctl_post
+
+import (
+	"gf-blog/app/library/auth"
+	"gf-blog/app/model"
+	"gf-blog/app/model/post"
+	"github.com/gogf/gf/g"
+	"github.com/gogf/gf/g/net/ghttp"
+	"github.com/gogf/gf/g/os/glog"
+	"net/http"
+	"strings"
+)
+
+const (
+	// 作者文章列表每页数量
+	authorPageSize = 20
+)
+
+// 当前作者的文章列表(包括草稿)，拥有管理权限时返回所有作者的文章
+func AuthorList(r *ghttp.Request) {
+	if !lib_auth.Check(r, lib_auth.PERM_WRITE_POST) {
+		return
+	}
+	page := r.GetInt("page", 1)
+	if page < 1 {
+		page = 1
+	}
+	author := getAuthor(r)
+	if lib_auth.Can(r, lib_auth.PERM_MANAGE) {
+		author = ""
+	}
+	posts, total, err := model_post.GetAuthorList(author, page, authorPageSize)
+	if err != nil {
+		writeAuthorError(r, err)
+		return
+	}
+	writeJson(r, http.StatusOK, 1, "", g.Map{
+		"total": total,
+		"page":  page,
+		"size":  authorPageSize,
+		"posts": posts,
+	})
+}
+
+// 创建或者更新文章，id参数为空时创建新文章，否则使用提交的内容覆盖原有文章。
+// 只能修改自己的文章，拥有管理权限时可以修改所有文章。
+// 参数: id、slug、title、markdown、status、publishAt(时间戳)、tags及categories(逗号分隔)
+func AuthorSave(r *ghttp.Request) {
+	if !lib_auth.Check(r, lib_auth.PERM_WRITE_POST) {
+		return
+	}
+	p := &model_post.Post{Author: getAuthor(r)}
+	if id := r.GetPostInt("id"); id > 0 {
+		exists, ok := getOwnPost(r, id)
+		if !ok {
+			return
+		}
+		p = exists
+	}
+	if p.Author == "" {
+		writeJson(r, http.StatusBadRequest, 0, "author is required", nil)
+		return
+	}
+	p.Slug       = r.GetPostString("slug")
+	p.Title      = r.GetPostString("title")
+	p.Markdown   = strings.Replace(r.GetPostString("markdown"), "\r\n", "\n", -1)
+	p.Status     = r.GetPostString("status")
+	p.PublishAt  = int64(r.GetPostInt("publishAt"))
+	p.Tags       = splitTerms(r.GetPostString("tags"))
+	p.Categories = splitTerms(r.GetPostString("categories"))
+	var err error
+	if p.Id > 0 {
+		err = model_post.Update(p)
+	} else {
+		_, err = model_post.Create(p)
+	}
+	if err != nil {
+		writeAuthorError(r, err)
+		return
+	}
+	glog.Cat("post").Printfln("post %d (%s) saved by %s", p.Id, p.Slug, p.Author)
+	writeJson(r, http.StatusOK, 1, "", p)
+}
+
+// 删除文章，只能删除自己的文章，拥有管理权限时可以删除所有文章
+func AuthorDelete(r *ghttp.Request) {
+	if !lib_auth.Check(r, lib_auth.PERM_WRITE_POST) {
+		return
+	}
+	p, ok := getOwnPost(r, r.GetPostInt("id"))
+	if !ok {
+		return
+	}
+	if err := model_post.Delete(p.Id); err != nil {
+		writeAuthorError(r, err)
+		return
+	}
+	glog.Cat("post").Printfln("post %d (%s) deleted by %s", p.Id, p.Slug, lib_auth.ClientIp(r))
+	writeJson(r, http.StatusOK, 1, "", nil)
+}
+
+// 获得当前用户可以修改的文章，文章不存在或者没有权限时输出错误信息并返回false
+func getOwnPost(r *ghttp.Request, id int) (*model_post.Post, bool) {
+	p, err := model_post.GetById(id)
+	if err != nil {
+		writeAuthorError(r, err)
+		return nil, false
+	}
+	if p == nil {
+		writeJson(r, http.StatusNotFound, 0, "post not found", nil)
+		return nil, false
+	}
+	if p.Author != getAuthor(r) && !lib_auth.Can(r, lib_auth.PERM_MANAGE) {
+		writeJson(r, http.StatusForbidden, 0, "permission denied", nil)
+		return nil, false
+	}
+	return p, true
+}
+
+// 获得当前作者名称，登录用户为用户名，使用管理令牌访问时为author参数
+func getAuthor(r *ghttp.Request) string {
+	if u := lib_auth.GetUser(r); u != nil {
+		return u.Username
+	}
+	return strings.TrimSpace(r.Get("author"))
+}
+
+// 将逗号分隔的标签或者分类转换为列表
+func splitTerms(value string) []string {
+	terms := make([]string, 0)
+	for _, term := range strings.Split(value, ",") {
+		if term = strings.TrimSpace(term); term != "" {
+			terms = append(terms, term)
+		}
+	}
+	return terms
+}
+
+// 输出文章保存错误，参数错误返回400，slug冲突返回409，其他错误返回500
+func writeAuthorError(r *ghttp.Request, err error) {
+	switch err {
+	case model_post.ErrInvalidSlug, model_post.ErrInvalidStatus:
+		writeJson(r, http.StatusBadRequest, 0, err.Error(), nil)
+	case model_post.ErrSlugExists:
+		writeJson(r, http.StatusConflict, 0, err.Error(), nil)
+	case model.ErrNoDatabase:
+		writeJson(r, http.StatusServiceUnavailable, 0, err.Error(), nil)
+	default:
+		glog.Cat("post").Printfln("post save error: %v", err)
+		writeJson(r, http.StatusInternalServerError, 0, "internal error", nil)
+	}
+}
+
+// 输出JSON数据
+func writeJson(r *ghttp.Request, status int, code int, msg string, data interface{}) {
+	if status != http.StatusOK {
+		r.Response.WriteHeader(status)
+	}
+	r.Response.WriteJson(g.Map{
+		"code": code,
+		"msg":  msg,
+		"data": data,
+	})
+}
@@ -0,0 +1,130 @@
+package ctl_taxonomy
+
+import (
+	"gf-blog/app/library/document"
+	"gf-blog/app/model/taxonomy"
+	"github.com/gogf/gf/g"
+	"github.com/gogf/gf/g/net/ghttp"
+	"github.com/gogf/gf/g/util/gpage"
+	"net/http"
+	"strings"
+)
+
+const (
+	// 标签及分类内容列表每页数量
+	itemPageSize = 20
+)
+
+// 标签索引页面，显示所有标签及内容数量
+func Tags(r *ghttp.Request) {
+	tags := model_taxonomy.GetTags()
+	if r.IsAjaxRequest() {
+		r.Response.WriteJson(g.Map{
+			"code": 1,
+			"msg":  "",
+			"data": g.Map{
+				"tags": tags,
+			},
+		})
+		return
+	}
+	params := getParams("标签")
+	params["mainTpl"] = "taxonomy/tags.html"
+	params["tags"]    = model_taxonomy.GetTagCloud(0)
+	r.Response.WriteTpl("layout.html", params)
+}
+
+// 标签页面，分页显示设置了该标签的内容
+func Tag(r *ghttp.Request) {
+	tag   := strings.TrimSpace(r.Get("tag"))
+	items := model_taxonomy.GetTagItems(tag)
+	if len(items) == 0 {
+		r.Response.WriteStatus(http.StatusNotFound)
+		return
+	}
+	writeItems(r, "标签: "+tag, g.Map{"tag": tag}, items)
+}
+
+// 分类索引页面，显示层级分类树及内容数量
+func Categories(r *ghttp.Request) {
+	categories := model_taxonomy.GetCategories()
+	if r.IsAjaxRequest() {
+		r.Response.WriteJson(g.Map{
+			"code": 1,
+			"msg":  "",
+			"data": g.Map{
+				"categories": categories,
+			},
+		})
+		return
+	}
+	params := getParams("分类")
+	params["mainTpl"]    = "taxonomy/categories.html"
+	params["categories"] = categories
+	r.Response.WriteTpl("layout.html", params)
+}
+
+// 分类页面，分页显示该分类及所有下级分类的内容
+func Category(r *ghttp.Request) {
+	// 模糊匹配路由同样会匹配到/categories，此时显示分类索引页面
+	if strings.Trim(r.Get("path"), "/") == "" {
+		Categories(r)
+		return
+	}
+	category := model_taxonomy.GetCategory(r.Get("path"))
+	if category == nil {
+		r.Response.WriteStatus(http.StatusNotFound)
+		return
+	}
+	items  := model_taxonomy.GetCategoryItems(category.Path)
+	params := g.Map{
+		"category": category.Path,
+		"children": category.Children,
+	}
+	writeItems(r, "分类: "+category.Path, params, items)
+}
+
+// 输出分页的内容列表，ajax请求时返回JSON数据，否则渲染内容列表页面
+func writeItems(r *ghttp.Request, title string, data g.Map, items []*model_taxonomy.Item) {
+	page  := r.GetInt("page", 1)
+	if page < 1 {
+		page = 1
+	}
+	total := len(items)
+	list  := model_taxonomy.Paginate(items, page, itemPageSize)
+	if r.IsAjaxRequest() {
+		data["total"] = total
+		data["page"]  = page
+		data["size"]  = itemPageSize
+		data["items"] = list
+		r.Response.WriteJson(g.Map{
+			"code": 1,
+			"msg":  "",
+			"data": data,
+		})
+		return
+	}
+	params := getParams(title)
+	for k, v := range data {
+		params[k] = v
+	}
+	params["mainTpl"] = "taxonomy/items.html"
+	params["heading"] = title
+	params["items"]   = list
+	params["total"]   = total
+	params["pager"]   = gpage.New(total, itemPageSize, page, r.URL.String()).GetContent(1)
+	r.Response.WriteTpl("layout.html", params)
+}
+
+// 标签及分类页面的公共模板变量，侧边栏显示文档的导航菜单及标签云
+func getParams(title string) g.Map {
+	if suffix := g.Config().GetString("document.title"); suffix != "" {
+		title += " - " + suffix
+	}
+	return g.Map{
+		"title"        : title,
+		"menu"         : lib_document.GetMenu("").Nodes,
+		"menuHtml"     : lib_document.RenderMenu(lib_document.GetMenu("").Nodes, ""),
+		"tagCloud"     : model_taxonomy.GetTagCloud(g.Config().GetInt("taxonomy.cloudSize")),
+	}
+}
@@ -0,0 +1,168 @@
+package ctl_user
+
+import (
+	"gf-blog/app/library/auth"
+	"gf-blog/app/library/document"
+	"gf-blog/app/model"
+	"gf-blog/app/model/user"
+	"github.com/gogf/gf/g"
+	"github.com/gogf/gf/g/net/ghttp"
+	"github.com/gogf/gf/g/os/gcache"
+	"github.com/gogf/gf/g/os/glog"
+	"github.com/gogf/gf/g/util/gconv"
+	"net/http"
+	"strings"
+)
+
+const (
+	// 同一IP连续登录失败的最大次数，超过后暂时禁止该IP登录
+	loginMaxFailures = 5
+	// 同一IP对同一用户名连续登录失败的最大次数，超过后暂时禁止该IP登录该用户，
+	// 计数不只按照用户名区分，避免任何人都可以通过错误密码锁定指定的账号
+	loginMaxUserFailures = 3
+	// 登录失败计数的有效时间(毫秒)
+	loginFailureExpire = 10 * 60 * 1000
+)
+
+// 登录页面及登录接口，GET请求渲染登录页面，POST请求校验用户名及密码。
+// 登录成功后ajax请求返回用户信息，否则跳转到redirect参数指定的站内地址
+func Login(r *ghttp.Request) {
+	redirect := getRedirect(r)
+	if r.Method != http.MethodPost {
+		writeLoginPage(r, redirect, "")
+		return
+	}
+	ip       := lib_auth.ClientIp(r)
+	username := r.GetPostString("username")
+	ipKey    := "login_fail_ip_" + ip
+	userKey  := "login_fail_user_" + ip + "_" + strings.ToLower(strings.TrimSpace(username))
+	if gconv.Int(gcache.Get(ipKey)) >= loginMaxFailures || gconv.Int(gcache.Get(userKey)) >= loginMaxUserFailures {
+		writeLoginError(r, http.StatusTooManyRequests, redirect, "too many failed attempts, please try again later")
+		return
+	}
+	u, err := model_user.Authenticate(username, r.GetPostString("password"))
+	if err != nil {
+		status := http.StatusUnauthorized
+		switch err {
+		case model_user.ErrInvalidLogin:
+			addFailure(ipKey)
+			addFailure(userKey)
+		case model.ErrNoDatabase:
+			status = http.StatusServiceUnavailable
+		default:
+			status = http.StatusInternalServerError
+		}
+		glog.Cat("user").Printfln("login %s from %s failed: %v", username, ip, err)
+		writeLoginError(r, status, redirect, err.Error())
+		return
+	}
+	// IP的失败计数不在登录成功时清除，避免通过登录自己的账号重置计数
+	gcache.Remove(userKey)
+	lib_auth.Login(r, u.AuthUser())
+	if r.IsAjaxRequest() {
+		writeJson(r, http.StatusOK, 1, "", u.AuthUser())
+		return
+	}
+	r.Response.RedirectTo(redirect)
+}
+
+// 退出登录，只接受携带CSRF令牌的POST请求，防止通过站外链接强制用户退出。
+// ajax请求时返回JSON数据，否则跳转到首页
+func Logout(r *ghttp.Request) {
+	if r.Method != http.MethodPost {
+		writeJson(r, http.StatusMethodNotAllowed, 0, "method not allowed", nil)
+		return
+	}
+	if !lib_auth.CheckCsrf(r) {
+		writeJson(r, http.StatusForbidden, 0, "invalid csrf token", nil)
+		return
+	}
+	lib_auth.Logout(r)
+	if r.IsAjaxRequest() {
+		writeJson(r, http.StatusOK, 1, "", nil)
+		return
+	}
+	r.Response.RedirectTo("/")
+}
+
+// 获得当前登录用户信息、权限及修改类请求需要提交的CSRF令牌，未登录时data为null
+func Me(r *ghttp.Request) {
+	u := lib_auth.GetUser(r)
+	if u == nil {
+		writeJson(r, http.StatusOK, 1, "", nil)
+		return
+	}
+	perms := make([]string, 0)
+	for _, perm := range []string{
+		lib_auth.PERM_MANAGE,
+		lib_auth.PERM_MANAGE_USER,
+		lib_auth.PERM_MODERATE,
+		lib_auth.PERM_EDIT_DOC,
+		lib_auth.PERM_WRITE_POST,
+		lib_auth.PERM_COMMENT,
+	} {
+		if u.Can(perm) {
+			perms = append(perms, perm)
+		}
+	}
+	writeJson(r, http.StatusOK, 1, "", g.Map{
+		"user":        u,
+		"permissions": perms,
+		"csrfToken":   lib_auth.CsrfToken(r),
+	})
+}
+
+// 增加登录失败计数，计数在最后一次失败之后的loginFailureExpire时间内有效
+func addFailure(key string) {
+	gcache.Set(key, gconv.Int(gcache.Get(key))+1, loginFailureExpire)
+}
+
+// 获得登录后的跳转地址，只允许站内相对路径，防止跳转到外部站点
+func getRedirect(r *ghttp.Request) string {
+	redirect := r.Get("redirect")
+	if !strings.HasPrefix(redirect, "/") || strings.HasPrefix(redirect, "//") || strings.Contains(redirect, `\`) {
+		return "/"
+	}
+	return redirect
+}
+
+// 登录失败时，ajax请求返回JSON错误信息，否则重新渲染登录页面并显示错误信息
+func writeLoginError(r *ghttp.Request, status int, redirect string, msg string) {
+	if r.IsAjaxRequest() {
+		writeJson(r, status, 0, msg, nil)
+		return
+	}
+	r.Response.WriteHeader(status)
+	writeLoginPage(r, redirect, msg)
+}
+
+// 渲染登录页面
+func writeLoginPage(r *ghttp.Request, redirect string, msg string) {
+	title := "登录"
+	if suffix := g.Config().GetString("document.title"); suffix != "" {
+		title += " - " + suffix
+	}
+	r.Response.WriteTpl("layout.html", g.Map{
+		"title"        : title,
+		"mainTpl"      : "user/login.html",
+		"menu"         : lib_document.GetMenu("").Nodes,
+		"menuHtml"     : lib_document.RenderMenu(lib_document.GetMenu("").Nodes, ""),
+		"redirect"     : redirect,
+		"username"     : r.GetPostString("username"),
+		"error"        : msg,
+		"user"         : lib_auth.GetUser(r),
+		"csrfToken"    : lib_auth.CsrfToken(r),
+	})
+}
+
+// 输出JSON数据，非200状态时同时设置HTTP状态码
+func writeJson(r *ghttp.Request, status int, code int, msg string, data interface{}) {
+	if status != http.StatusOK {
+		r.Response.WriteHeader(status)
+	}
+	r.Response.WriteJson(g.Map{
+		"code": code,
+		"msg":  msg,
+		"data": data,
+	})
+}
@@ -0,0 +1,236 @@
+package lib_auth
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"github.com/gogf/gf/g"
+	"github.com/gogf/gf/g/net/ghttp"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	// 用户角色，权限从高到低
+	ROLE_ADMIN  = "admin"  // 管理员，拥有所有权限
+	ROLE_EDITOR = "editor" // 编辑，可以编辑文档、审核评论
+	ROLE_AUTHOR = "author" // 作者，可以撰写博客文章
+	ROLE_READER = "reader" // 读者，只能发表评论
+
+	// 权限
+	PERM_MANAGE      = "manage"           // 访问管理接口(/admin/*)
+	PERM_MANAGE_USER = "user.manage"      // 管理用户
+	PERM_MODERATE    = "comment.moderate" // 审核评论
+	PERM_EDIT_DOC    = "doc.edit"         // 在线编辑文档
+	PERM_WRITE_POST  = "post.write"       // 撰写博客文章
+	PERM_COMMENT     = "comment.write"    // 发表评论
+
+	// 登录用户在session中的键名
+	sessionKey = "auth_user"
+	// CSRF令牌在session中的键名
+	csrfKey = "auth_csrf"
+)
+
+// 角色拥有的权限
+var rolePermissions = map[string][]string{
+	ROLE_ADMIN:  {PERM_MANAGE, PERM_MANAGE_USER, PERM_MODERATE, PERM_EDIT_DOC, PERM_WRITE_POST, PERM_COMMENT},
+	ROLE_EDITOR: {PERM_MANAGE, PERM_MODERATE, PERM_EDIT_DOC, PERM_WRITE_POST, PERM_COMMENT},
+	ROLE_AUTHOR: {PERM_WRITE_POST, PERM_COMMENT},
+	ROLE_READER: {PERM_COMMENT},
+}
+
+// 登录用户信息，登录时保存在session中
+type User struct {
+	Id       int    `json:"id"`
+	Username string `json:"username"`
+	Nickname string `json:"nickname"`
+	Email    string `json:"email"`
+	Role     string `json:"role"`
+}
+
+// 判断是否为有效的角色
+func IsRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+// 判断角色是否拥有指定权限
+func HasPermission(role string, perm string) bool {
+	for _, p := range rolePermissions[role] {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
+
+// 判断用户是否拥有指定权限
+func (u *User) Can(perm string) bool {
+	return u != nil && HasPermission(u.Role, perm)
+}
+
+// 用户的显示名称，未设置昵称时为用户名
+func (u *User) DisplayName() string {
+	if u.Nickname != "" {
+		return u.Nickname
+	}
+	return u.Username
+}
+
+// 将登录用户保存到session中。登录前的session数据作废并使用新的session id，防止会话固定攻击
+func Login(r *ghttp.Request, user *User) {
+	r.Session.Clear()
+	r.Cookie.SetSessionId("")
+	r.Session = nil
+	r.Session = ghttp.GetSession(r)
+	r.Session.Set(sessionKey, user)
+}
+
+// 退出登录，清除session数据
+func Logout(r *ghttp.Request) {
+	r.Session.Clear()
+}
+
+// 获得当前登录用户，未登录时返回nil。
+// 用户信息为登录时的快照，角色变更需要重新登录后生效
+func GetUser(r *ghttp.Request) *User {
+	if user, ok := r.Session.Get(sessionKey).(*User); ok {
+		return user
+	}
+	return nil
+}
+
+// 判断当前请求是否拥有指定权限：登录用户的角色拥有该权限，
+// 或者通过X-Admin-Token请求头提供了admin.token配置的令牌(用于自动化脚本，拥有所有权限)
+func Can(r *ghttp.Request, perm string) bool {
+	return GetUser(r).Can(perm) || isAdminToken(r)
+}
+
+// 校验当前请求的权限，没有权限时返回401(未登录)或者403(权限不足)状态及错误信息，
+// 调用端需要在返回false时结束请求处理
+func Check(r *ghttp.Request, perm string) bool {
+	if Can(r, perm) {
+		return true
+	}
+	if GetUser(r) == nil {
+		writeDenied(r, http.StatusUnauthorized, "unauthorized")
+	} else {
+		writeDenied(r, http.StatusForbidden, "permission denied")
+	}
+	return false
+}
+
+// 获得当前会话的CSRF令牌，不存在时生成。
+// 页面表单通过_csrf参数、ajax请求通过X-CSRF-Token请求头提交
+func CsrfToken(r *ghttp.Request) string {
+	if token := r.Session.GetString(csrfKey); token != "" {
+		return token
+	}
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	token := hex.EncodeToString(b)
+	r.Session.Set(csrfKey, token)
+	return token
+}
+
+// 校验修改类请求(非GET/HEAD/OPTIONS)的CSRF令牌，使用管理令牌的请求不需要校验
+func CheckCsrf(r *ghttp.Request) bool {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	if isAdminToken(r) {
+		return true
+	}
+	token := r.Session.GetString(csrfKey)
+	given := r.Header.Get("X-CSRF-Token")
+	if given == "" {
+		given = r.Get("_csrf")
+	}
+	return token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(given)) == 1
+}
+
+// 权限校验的路由HOOK，用于 BindHookHandler(pattern, ghttp.HOOK_BEFORE_SERVE, Guard(perm))。
+// 未登录的页面(GET非ajax)请求跳转到登录页面，其他请求返回JSON错误信息，修改类请求同时校验CSRF令牌
+func Guard(perm string) ghttp.HandlerFunc {
+	return func(r *ghttp.Request) {
+		if Can(r, perm) {
+			if CheckCsrf(r) {
+				return
+			}
+			writeDenied(r, http.StatusForbidden, "invalid csrf token")
+			r.ExitAll()
+		}
+		if GetUser(r) == nil && r.Method == http.MethodGet && !r.IsAjaxRequest() {
+			// RedirectTo只退出当前HOOK，这里需要退出整个请求流程
+			r.Response.Header().Set("Location", "/login?redirect="+url.QueryEscape(r.URL.RequestURI()))
+			r.Response.WriteHeader(http.StatusFound)
+		} else {
+			Check(r, perm)
+		}
+		r.ExitAll()
+	}
+}
+
+// 获得请求的客户端IP。只有直接连接的地址属于setting.trustedProxies配置的反向代理时，
+// 才使用X-Forwarded-For、X-Real-IP请求头中的地址，防止客户端伪造IP绕过访问频率限制
+func ClientIp(r *ghttp.Request) string {
+	ip := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		ip = host
+	}
+	if !isTrustedProxy(ip) {
+		return ip
+	}
+	// 从右向左取第一个非可信代理的地址，左侧的地址可以被客户端伪造
+	forwarded := strings.Split(r.Header.Get("X-Forwarded-For"), ",")
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		if addr := strings.TrimSpace(forwarded[i]); addr != "" && !isTrustedProxy(addr) {
+			return addr
+		}
+	}
+	if real := strings.TrimSpace(r.Header.Get("X-Real-IP")); real != "" {
+		return real
+	}
+	return ip
+}
+
+// 判断IP是否属于可信的反向代理，配置项为IP或者CIDR列表
+func isTrustedProxy(ip string) bool {
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return false
+	}
+	for _, proxy := range g.Config().GetStrings("setting.trustedProxies") {
+		if _, network, err := net.ParseCIDR(proxy); err == nil {
+			if network.Contains(addr) {
+				return true
+			}
+		} else if proxyIp := net.ParseIP(proxy); proxyIp != nil && proxyIp.Equal(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// 判断请求是否通过请求头提供了有效的管理令牌，未配置令牌时始终返回false。
+// 令牌不接受URL参数，避免写入访问日志及Referer请求头
+func isAdminToken(r *ghttp.Request) bool {
+	token := g.Config().GetString("admin.token")
+	given := r.Header.Get("X-Admin-Token")
+	return token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(given)) == 1
+}
+
+// 返回权限校验失败的状态码及JSON错误信息
+func writeDenied(r *ghttp.Request, status int, msg string) {
+	r.Response.WriteHeader(status)
+	r.Response.WriteJson(g.Map{
+		"code": 0,
+		"msg":  msg,
+		"data": nil,
+	})
+}
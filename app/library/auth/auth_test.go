@@ -0,0 +1,209 @@
+package lib_auth
+
+import (
+	"fmt"
+	"github.com/gogf/gf/g"
+	"github.com/gogf/gf/g/net/ghttp"
+	"github.com/gogf/gf/g/os/gfile"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	// 测试配置中的管理令牌
+	testAdminToken = "admin-token"
+)
+
+// 测试服务的访问地址，在TestMain中启动
+var testBaseUrl string
+
+// 创建临时配置并启动测试使用的Web Server
+func TestMain(m *testing.M) {
+	dir, err := ioutil.TempDir("", "gf-blog-auth")
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	config := fmt.Sprintf("[setting]\n    trustedProxies = [\"10.0.0.0/8\", \"192.168.1.1\"]\n[admin]\n    token = %q\n", testAdminToken)
+	gfile.PutContents(filepath.Join(dir, "config.toml"), config)
+	g.Config().SetPath(dir)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	port := l.Addr().(*net.TCPAddr).Port
+	l.Close()
+	s := g.Server("auth-test")
+	s.SetPort(port)
+	s.BindHandler("/token", func(r *ghttp.Request) {
+		r.Response.Write(CsrfToken(r))
+	})
+	s.BindHandler("/check", func(r *ghttp.Request) {
+		r.Response.Write(fmt.Sprint(CheckCsrf(r)))
+	})
+	s.BindHandler("/ip", func(r *ghttp.Request) {
+		r.Response.Write(ClientIp(r))
+	})
+	s.BindHandler("/login", func(r *ghttp.Request) {
+		Login(r, &User{Id: 1, Username: "editor1", Role: ROLE_EDITOR})
+	})
+	s.BindHandler("/can", func(r *ghttp.Request) {
+		r.Response.Write(fmt.Sprint(Can(r, r.Get("perm"))))
+	})
+	s.Start()
+	testBaseUrl = fmt.Sprintf("http://127.0.0.1:%d", port)
+	time.Sleep(200 * time.Millisecond)
+
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// 创建保存cookie的客户端
+func newClient() *http.Client {
+	jar, _ := cookiejar.New(nil)
+	return &http.Client{Jar: jar}
+}
+
+// 发送请求并返回响应内容
+func request(t *testing.T, client *http.Client, method string, path string, form url.Values, header map[string]string) string {
+	req, err := http.NewRequest(method, testBaseUrl+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := ioutil.ReadAll(resp.Body)
+	return string(body)
+}
+
+func TestHasPermission(t *testing.T) {
+	cases := []struct {
+		role string
+		perm string
+		want bool
+	}{
+		{ROLE_ADMIN, PERM_MANAGE_USER, true},
+		{ROLE_ADMIN, PERM_COMMENT, true},
+		{ROLE_EDITOR, PERM_MANAGE, true},
+		{ROLE_EDITOR, PERM_EDIT_DOC, true},
+		{ROLE_EDITOR, PERM_MANAGE_USER, false},
+		{ROLE_AUTHOR, PERM_WRITE_POST, true},
+		{ROLE_AUTHOR, PERM_EDIT_DOC, false},
+		{ROLE_AUTHOR, PERM_MANAGE, false},
+		{ROLE_READER, PERM_COMMENT, true},
+		{ROLE_READER, PERM_WRITE_POST, false},
+		{"unknown", PERM_COMMENT, false},
+		{ROLE_ADMIN, "unknown", false},
+	}
+	for _, c := range cases {
+		if got := HasPermission(c.role, c.perm); got != c.want {
+			t.Errorf("HasPermission(%s, %s) = %v, want %v", c.role, c.perm, got, c.want)
+		}
+		if got := (&User{Role: c.role}).Can(c.perm); got != c.want {
+			t.Errorf("User{%s}.Can(%s) = %v, want %v", c.role, c.perm, got, c.want)
+		}
+	}
+	var u *User
+	if u.Can(PERM_COMMENT) {
+		t.Errorf("nil user should have no permission")
+	}
+	if IsRole("unknown") || !IsRole(ROLE_READER) {
+		t.Errorf("IsRole mismatch")
+	}
+}
+
+func TestCheckCsrf(t *testing.T) {
+	client := newClient()
+	token  := request(t, client, "GET", "/token", nil, nil)
+	if len(token) != 32 {
+		t.Fatalf("CsrfToken = %q, want 32 hex chars", token)
+	}
+	if again := request(t, client, "GET", "/token", nil, nil); again != token {
+		t.Errorf("CsrfToken changed within session: %q != %q", again, token)
+	}
+	cases := []struct {
+		name   string
+		client *http.Client
+		method string
+		form   url.Values
+		header map[string]string
+		want   string
+	}{
+		{"safe method", client, "GET", nil, nil, "true"},
+		{"missing token", client, "POST", nil, nil, "false"},
+		{"header token", client, "POST", nil, map[string]string{"X-CSRF-Token": token}, "true"},
+		{"form token", client, "POST", url.Values{"_csrf": {token}}, nil, "true"},
+		{"wrong token", client, "POST", url.Values{"_csrf": {token + "x"}}, nil, "false"},
+		{"other session", newClient(), "POST", nil, map[string]string{"X-CSRF-Token": token}, "false"},
+		{"empty session token", newClient(), "DELETE", url.Values{"_csrf": {""}}, nil, "false"},
+		{"admin token", newClient(), "POST", nil, map[string]string{"X-Admin-Token": testAdminToken}, "true"},
+		{"wrong admin token", newClient(), "POST", nil, map[string]string{"X-Admin-Token": "wrong"}, "false"},
+		{"admin token param", newClient(), "POST", url.Values{"token": {testAdminToken}}, nil, "false"},
+	}
+	for _, c := range cases {
+		if got := request(t, c.client, c.method, "/check", c.form, c.header); got != c.want {
+			t.Errorf("%s: CheckCsrf = %s, want %s", c.name, got, c.want)
+		}
+	}
+}
+
+func TestLoginRotatesSession(t *testing.T) {
+	client := newClient()
+	token  := request(t, client, "GET", "/token", nil, nil)
+	if got := request(t, client, "GET", "/can?perm="+PERM_EDIT_DOC, nil, nil); got != "false" {
+		t.Errorf("Can before login = %s, want false", got)
+	}
+	request(t, client, "GET", "/login", nil, nil)
+	if got := request(t, client, "GET", "/can?perm="+PERM_EDIT_DOC, nil, nil); got != "true" {
+		t.Errorf("Can after login = %s, want true", got)
+	}
+	if got := request(t, client, "GET", "/can?perm="+PERM_MANAGE_USER, nil, nil); got != "false" {
+		t.Errorf("Can(%s) after login = %s, want false", PERM_MANAGE_USER, got)
+	}
+	// 登录后使用新的会话，登录前的CSRF令牌失效
+	if got := request(t, client, "GET", "/token", nil, nil); got == token {
+		t.Errorf("CsrfToken not rotated after login")
+	}
+}
+
+func TestClientIp(t *testing.T) {
+	client := newClient()
+	// 直接连接的地址不是可信代理时，忽略客户端提供的请求头
+	header := map[string]string{"X-Real-IP": "1.2.3.4", "X-Forwarded-For": "5.6.7.8"}
+	if got := request(t, client, "GET", "/ip", nil, header); got != "127.0.0.1" {
+		t.Errorf("ClientIp = %s, want 127.0.0.1", got)
+	}
+	cases := []struct {
+		ip   string
+		want bool
+	}{
+		{"10.1.2.3", true},
+		{"192.168.1.1", true},
+		{"192.168.1.2", false},
+		{"127.0.0.1", false},
+		{"invalid", false},
+	}
+	for _, c := range cases {
+		if got := isTrustedProxy(c.ip); got != c.want {
+			t.Errorf("isTrustedProxy(%s) = %v, want %v", c.ip, got, c.want)
+		}
+	}
+}
@@ -3,147 +3,189 @@ package lib_document
 import (
 	"fmt"
 	"github.com/gogf/gf/g"
-	"github.com/gogf/gf/g/container/garray"
 	"github.com/gogf/gf/g/os/gcache"
 	"github.com/gogf/gf/g/os/gfcache"
 	"github.com/gogf/gf/g/os/gfile"
 	"github.com/gogf/gf/g/os/glog"
-	"github.com/gogf/gf/g/os/gproc"
 	"github.com/gogf/gf/g/text/gregex"
 	"github.com/gogf/gf/g/text/gstr"
 	"github.com/gogf/gf/g/util/gconv"
-	//"gopkg.in/russross/blackfriday"
-	"github.com/russross/blackfriday"
+	"net/url"
 	"strings"
+	"sync"
+)
+
+const (
+	// 空结果(文档不存在、没有提交记录等)的缓存时间(毫秒)，避免任意路径的请求长期占用缓存
+	negativeCacheExpire = 60 * 1000
 )
 
 var (
-	// 文档缓存
+	// 静态文件的扩展名，带有这些扩展名的请求路径不作为文档路径处理。
+	// 文档路径本身可以包含"."，例如版本首页 /v1.5
+	staticExts = map[string]bool{
+		".css": true, ".js": true, ".map": true, ".json": true, ".xml": true, ".txt": true,
+		".png": true, ".jpg": true, ".jpeg": true, ".gif": true, ".svg": true, ".ico": true, ".webp": true, ".bmp": true,
+		".woff": true, ".woff2": true, ".ttf": true, ".eot": true, ".otf": true,
+		".pdf": true, ".zip": true, ".gz": true, ".tar": true, ".mp3": true, ".mp4": true, ".webm": true,
+		".html": true, ".htm": true, ".md": true,
+	}
+	// 当前版本的文档缓存，其他版本使用各自独立的缓存
 	cache = gcache.New()
+	// 文档版本库更新互斥锁，保证更新过程不会重叠执行
+	updateMu = sync.Mutex{}
 )
 
-// 更新doc版本库
-func UpdateDocGit() {
-	err := gproc.ShellRun(
-		fmt.Sprintf(`cd %s && git pull origin master`, g.Config().GetString("document.path")),
-	)
+// 更新doc版本库，更新过程串行执行，返回git命令的输出内容
+func UpdateDocGit() (string, error) {
+	updateMu.Lock()
+	defer updateMu.Unlock()
+	root        := getDocRoot()
+	oldHead     := getHead(root)
+	output, err := pullDocGit(root, g.Config().GetBool("editor.push"))
+	output = strings.TrimSpace(output)
 	if err == nil {
-		// 每次文档的更新都要清除缓存对象数据
-		cache.Clear()
+		// 根据更新前后的版本差异，只清除变更文件相关的缓存数据，
+		// 无法获得差异时清除所有缓存数据
+		newHead := getHead(root)
+		if oldHead != newHead {
+			if files, err := getChangedFiles(root, oldHead, newHead); err == nil {
+				RefreshFiles(files)
+			} else {
+				glog.Cat("doc-hook").Printfln("doc hook diff error, clear all caches: %v", err)
+				getDefaultVersion().reset()
+			}
+		}
 
-		glog.Cat("doc-hook").Printfln("doc hook updates")
+		glog.Cat("doc-hook").Printfln("doc hook updates %s..%s: %s", shortHash(oldHead), shortHash(newHead), output)
 	} else {
-		glog.Cat("doc-hook").Printfln("doc hook updates error: %v",  err)
+		glog.Cat("doc-hook").Printfln("doc hook updates error: %v", err)
 	}
+	// 同时更新其他版本的worktree
+	setupVersions()
+	return output, err
 }
 
-// 根据关键字进行markdown文档搜索，返回文档path列表
-func SearchMdByKey(key string) []string {
-	glog.Cat("search").Println(key)
-	v := cache.GetOrSetFunc("doc_search_result_" + key, func() interface{} {
-		// 当该key的检索缓存不存在时，执行检索
-		array    := garray.NewStringArray(true)
-		docPath  := g.Config().GetString("document.path")
-		paths    := cache.GetOrSetFunc("doc_files_recursive", func() interface{} {
-			// 当目录列表不存在时，执行检索
-			paths, _ := gfile.ScanDir(docPath, "*.md", true)
-			return paths
-		}, 0)
-		// 遍历markdown文件列表，执行字符串搜索
-		for _, path := range gconv.Strings(paths) {
-			content := gfcache.GetContents(path)
-			if len(content) > 0 {
-				if strings.Index(content, key) != -1 {
-					index := gstr.Replace(path, ".md", "")
-					index  = gstr.Replace(index, docPath, "")
-					array.Append(index)
-				}
-			}
-		}
-		return array.Slice()
-	}, 0)
+// 将markdown文件绝对路径转换为文档uri路径，非当前版本的文档包含版本前缀，例如: /v1.5/net/ghttp
+func getUriByFilePath(path string) string {
+	v   := getVersionByFilePath(path)
+	uri := strings.TrimSuffix(strings.TrimPrefix(path, v.root), ".md")
+	return "/" + v.prefix() + strings.Trim(gstr.Replace(uri, gfile.Separator, "/"), "/")
+}
 
-	return gconv.Strings(v)
+// 将文档uri路径转换为所属版本目录下的markdown文件绝对路径
+func getFilePathByUri(uri string) string {
+	v, path := getVersionByUri(uri)
+	return v.root + gfile.Separator + gstr.Replace(path, "/", gfile.Separator) + ".md"
 }
 
-// 根据path参数获得层级显示的title
-func GetTitleByPath(path string) string {
-	v := cache.GetOrSetFunc("title_by_path_" + path, func() interface{} {
-		type lineItem struct {
-			indent int
-			name   string
-		}
-		path        = strings.TrimLeft(path, "/")
-		array      := make([]lineItem, 0)
-		mdContent  := GetMarkdown("menus")
-		lines      := strings.Split(mdContent, "\n")
-		indent     := 0
-		for _, line := range lines {
-			match, _ := gregex.MatchString(`(\s*)\*\s+\[(.+)\]\((.+)\)`, line)
-			if len(match) == 4 {
-				item := lineItem{
-					indent : len(match[1]),
-					name   : match[2],
-				}
-				mdPath := gstr.Replace(match[3], ".md", "")
-				if item.indent > indent || len(array) == 0 {
-					array = append(array, item)
-				} else if len(match[1]) == indent {
-					array[len(array) - 1] = item
-				} else {
-					newArray := make([]lineItem, 0)
-					for _, v := range array {
-						if v.indent < item.indent {
-							newArray = append(newArray, v)
-						}
-					}
-					newArray = append(newArray, item)
-					array    = newArray
-				}
-				indent = item.indent
-				if mdPath == path {
-					break
-				}
-			}
+// 判断请求路径是否为静态文件(根据扩展名)
+func IsStaticPath(path string) bool {
+	return staticExts[strings.ToLower(gfile.Ext(path))]
+}
+
+// 判断uri路径是否为非当前版本的版本首页，例如: /v1.5
+func IsVersionRoot(path string) bool {
+	path = strings.Trim(path, "/")
+	for _, v := range GetVersions()[1:] {
+		if path == v.Name {
+			return true
 		}
-		if len(array) > 0 {
-			title := ""
-			for i := len(array) - 1; i >= 0; i-- {
-				if len(title) > 0 {
-					title += " - " + array[i].name
-				} else {
-					title  = array[i].name
-				}
+	}
+	return false
+}
+
+// 对uri路径的每一级分别进行URL编码，保留分隔符"/"，用于模板中链接地址的输出(模板函数urlpath)
+func EscapePath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// 获得所有版本的文档uri路径列表(不包含前导"/")，不包含菜单文档及隐藏的草稿文档
+func GetDocumentPaths() []string {
+	paths := make([]string, 0)
+	for _, v := range GetVersions() {
+		for _, file := range v.getMdFiles() {
+			path := strings.TrimLeft(getUriByFilePath(file), "/")
+			if path == GetMenuPath(path) || isHiddenDraft(gfcache.GetContents(file)) {
+				continue
 			}
-			return title
+			paths = append(paths, path)
 		}
-		return nil
-	}, 0)
-	if v != nil {
-		return v.(string)
 	}
-	return ""
+	return paths
+}
+
+// 判断uri路径是否为可以访问的文档：路径合法、文档存在，并且不是菜单文档或者隐藏的草稿
+func IsDocumentPath(path string) bool {
+	path = strings.Trim(path, "/")
+	if path == "" || strings.Contains(path, "..") {
+		return false
+	}
+	content := GetMarkdown(path)
+	return content != "" && path != GetMenuPath(path) && !isHiddenDraft(content)
+}
+
+// 获得当前版本文档目录的绝对路径
+func getDocRoot() string {
+	docPath := g.Config().GetString("document.path")
+	if realPath := gfile.RealPath(docPath); realPath != "" {
+		return realPath
+	}
+	return docPath
+}
+
+// 根据path参数获得层级显示的title，例如: 路由注册 - WebServer - 核心模块
+func GetTitleByPath(path string) string {
+	path       = strings.Trim(path, "/")
+	version, _ := getVersionByUri(path)
+	key        := "title_by_path_" + path
+	if v := version.cache.Get(key); v != nil {
+		return gconv.String(v)
+	}
+	names := make([]string, 0)
+	nodes := GetBreadcrumb(path)
+	for i := len(nodes) - 1; i >= 0; i-- {
+		names = append(names, nodes[i].Title)
+	}
+	title := strings.Join(names, " - ")
+	if title == "" {
+		version.cache.Set(key, title, negativeCacheExpire)
+	} else {
+		version.cache.Set(key, title, 0)
+	}
+	return title
 }
 
 // 获得指定uri路径的markdown文件内容
 func GetMarkdown(path string) string {
-	mdRoot  := g.Config().GetString("document.path")
-	content := gfcache.GetContents(mdRoot + gfile.Separator + path + ".md")
+	content := gfcache.GetContents(getFilePathByUri(path))
 	return content
 }
 
-// 获得解析为html的markdown文件内容
+// 获得解析为html的markdown文件内容(不包含front matter)
 func GetParsed(path string) string {
-	return ParseMarkdown(GetMarkdown(path))
+	_, content := GetMarkdownWithMeta(path)
+	return ParseMarkdown(content)
 }
 
 // 解析markdown为html
 func ParseMarkdown(content string) string {
+	content, _ = ParseMarkdownWithToc(content)
+	return content
+}
+
+// 解析markdown为html，同时返回根据标题生成的目录树
+func ParseMarkdownWithToc(content string) (string, []*TocItem) {
 	if content == "" {
-		return ""
+		return "", nil
 	}
+	// 标题生成唯一的锚点ID，并提取目录
+	content, toc := renderMarkdown(content)
 	// src及href 替换为/xxx模式的绝对连接
-	content    = string(blackfriday.Run([]byte(content)))
 	pattern   := `(src|href)=["'](.+?)["']`
 	content, _ = gregex.ReplaceStringFunc(pattern, content, func(s string) string {
 		match, _ := gregex.MatchString(pattern, gstr.Replace(s, ".md", ""))
@@ -154,5 +196,5 @@ func ParseMarkdown(content string) string {
 		}
 		return s
 	})
-	return content
+	return content, toc
 }
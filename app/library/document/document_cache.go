@@ -0,0 +1,111 @@
+package lib_document
+
+import (
+	"github.com/gogf/gf/g/container/garray"
+	"github.com/gogf/gf/g/os/gcache"
+	"github.com/gogf/gf/g/os/gfcache"
+	"github.com/gogf/gf/g/os/gfile"
+	"strings"
+)
+
+// 文档变更监听函数，参数为变更(包括删除)的文档uri路径列表
+type ChangeListener func(uris []string)
+
+var (
+	// 文档变更监听函数列表
+	changeListeners = garray.New()
+)
+
+// 添加文档变更监听函数，文档缓存刷新后异步调用
+func AddChangeListener(listener ChangeListener) {
+	changeListeners.Append(listener)
+}
+
+// 刷新指定文件(绝对路径)相关的缓存数据：
+// 文件内容缓存(gfcache)、文档列表、导航菜单、层级标题及全文检索索引，并对变更的文档重新预热。
+// 文件可以属于不同的文档版本，各版本的缓存数据分别刷新。
+func RefreshFiles(paths []string) {
+	files   := make(map[*Version]map[string]struct{})
+	uris    := make([]string, 0)
+	changed := make([]string, 0)
+	listing := make(map[*Version]bool)
+	menus   := make(map[*Version]bool)
+	for _, path := range paths {
+		removeFileCache(path)
+		if gfile.Ext(path) != ".md" {
+			continue
+		}
+		v := getVersionByFilePath(path)
+		if _, ok := files[v]; !ok {
+			files[v] = make(map[string]struct{})
+			for _, file := range v.getMdFiles() {
+				files[v][file] = struct{}{}
+			}
+		}
+		index := v.getSearchIndex()
+		uri   := getUriByFilePath(path)
+		changed = append(changed, uri)
+		v.cache.Remove("title_by_path_" + strings.TrimLeft(uri, "/"))
+		v.cache.Remove("doc_history_" + strings.TrimLeft(uri, "/"))
+		if strings.TrimLeft(uri, "/") == GetMenuPath(uri) {
+			menus[v] = true
+		}
+		_, listed := files[v][path]
+		if gfile.Exists(path) {
+			if !listed {
+				listing[v] = true
+			}
+			if content := gfcache.GetContents(path); isHiddenDraft(content) {
+				index.Remove(uri)
+			} else {
+				index.Add(uri, content)
+			}
+			uris = append(uris, uri)
+		} else {
+			if listed {
+				listing[v] = true
+			}
+			index.Remove(uri)
+		}
+	}
+	for v := range files {
+		// 文档元数据中的别名、标签分类、文档的提交时间及最近更新列表可能变更
+		v.cache.Remove("doc_aliases")
+		v.cache.Remove("doc_terms")
+		v.cache.Remove("doc_commit_times")
+		for _, key := range v.cache.KeyStrings() {
+			if strings.HasPrefix(key, "doc_recent_") {
+				v.cache.Remove(key)
+			}
+		}
+		// 文档新增或者删除时，重新检索文档列表
+		if listing[v] {
+			v.cache.Remove("doc_files_recursive")
+			v.getMdFiles()
+		}
+		// 菜单变更时，导航菜单树及所有层级标题都需要重新计算
+		if menus[v] {
+			for _, key := range v.cache.KeyStrings() {
+				if strings.HasPrefix(key, "doc_menu_") || strings.HasPrefix(key, "title_by_path_") {
+					v.cache.Remove(key)
+				}
+			}
+			for _, lang := range GetLanguages() {
+				GetMenu(v.prefix() + lang.prefix() + "menus")
+			}
+		}
+	}
+	for _, uri := range uris {
+		GetTitleByPath(uri)
+	}
+	if len(changed) > 0 {
+		for _, v := range changeListeners.Slice() {
+			go v.(ChangeListener)(changed)
+		}
+	}
+}
+
+// 清除gfcache中的文件内容缓存，键名需要与gfcache内部的键名规则保持一致
+func removeFileCache(path string) {
+	gcache.Remove("gf.gfcache:" + path)
+}
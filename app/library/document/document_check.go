@@ -0,0 +1,183 @@
+package lib_document
+
+import (
+	"github.com/gogf/gf/g"
+	"github.com/gogf/gf/g/os/gfcache"
+	"github.com/gogf/gf/g/os/gfile"
+	"github.com/gogf/gf/g/text/gregex"
+	"html"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	// 失效链接类型
+	BROKEN_DOCUMENT = "document" // 链接的文档不存在
+	BROKEN_ANCHOR   = "anchor"   // 链接的文档存在，但是锚点(标题)不存在
+	BROKEN_IMAGE    = "image"    // 图片文件不存在
+	BROKEN_FILE     = "file"     // 链接的静态文件不存在
+	BROKEN_MENU     = "menu"     // 菜单项指向的文档不存在
+
+	// 静态文件目录，与WebServer的ServerRoot一致
+	staticRoot = "public"
+	// 渲染后html中的链接及图片属性
+	checkLinkPattern = `<(\w+)[^>]*?\s(href|src)="([^"]*)"`
+	// 渲染后html中的锚点ID属性
+	checkIdPattern = `\sid="([^"]+)"`
+)
+
+// 失效链接
+type BrokenLink struct {
+	Path string `json:"path"` // 链接所在的文档uri路径(菜单项为菜单文档路径)
+	Link string `json:"link"` // 链接地址
+	Type string `json:"type"` // 失效类型
+	Text string `json:"text"` // 菜单项标题，普通链接为空
+}
+
+// 链接检查过程中的上下文，缓存已经检查过的文档锚点
+type linkChecker struct {
+	anchors map[string]map[string]bool // 文档uri路径 => 锚点ID集合
+	ignores []string                   // 不检查的链接前缀(非文档页面的路由)
+}
+
+// 检查所有版本、所有语言文档中的站内链接、锚点、图片，以及菜单项，返回失效链接列表
+func CheckLinks() []*BrokenLink {
+	checker := &linkChecker{
+		anchors: make(map[string]map[string]bool),
+		ignores: g.Config().GetStrings("check.ignore"),
+	}
+	broken := make([]*BrokenLink, 0)
+	for _, path := range GetDocumentPaths() {
+		_, markdown := GetMarkdownWithMeta(path)
+		content, _ := ParseMarkdownWithToc(markdown)
+		broken = append(broken, checker.checkContent(path, content)...)
+	}
+	for _, v := range GetVersions() {
+		for _, lang := range GetLanguages() {
+			menuPath := v.prefix() + lang.prefix() + "menus"
+			if !gfile.Exists(getFilePathByUri(menuPath)) {
+				continue
+			}
+			for _, node := range GetMenu(menuPath).Pages() {
+				if !checker.documentExists(node.Path) {
+					broken = append(broken, &BrokenLink{
+						Path: menuPath,
+						Link: node.Url(),
+						Type: BROKEN_MENU,
+						Text: node.Title,
+					})
+				}
+			}
+		}
+	}
+	sort.SliceStable(broken, func(i, j int) bool {
+		return broken[i].Path < broken[j].Path
+	})
+	return broken
+}
+
+// 检查单个文档渲染后html内容中的链接
+func (c *linkChecker) checkContent(path string, content string) []*BrokenLink {
+	broken   := make([]*BrokenLink, 0)
+	match, _ := gregex.MatchAllString(checkLinkPattern, content)
+	for _, m := range match {
+		link := html.UnescapeString(m[3])
+		if typ := c.checkLink(path, m[1], link); typ != "" {
+			broken = append(broken, &BrokenLink{Path: path, Link: link, Type: typ})
+		}
+	}
+	return broken
+}
+
+// 检查单个链接，有效时返回空字符串，否则返回失效类型
+func (c *linkChecker) checkLink(path string, tag string, link string) string {
+	// 外部链接不做检查
+	if link == "" || strings.HasPrefix(link, "//") || strings.Contains(link, "://") || strings.HasPrefix(link, "mailto:") {
+		return ""
+	}
+	target, anchor := link, ""
+	if i := strings.IndexByte(target, '#'); i >= 0 {
+		target, anchor = target[:i], target[i+1:]
+	}
+	if i := strings.IndexByte(target, '?'); i >= 0 {
+		target = target[:i]
+	}
+	// 链接中的路径可能经过URL编码，例如中文文档路径
+	if unescaped, err := url.PathUnescape(target); err == nil {
+		target = unescaped
+	}
+	if target == "" {
+		target = "/" + path
+	}
+	for _, prefix := range c.ignores {
+		if target == prefix || strings.HasPrefix(target, strings.TrimRight(prefix, "/")+"/") {
+			return ""
+		}
+	}
+	// 带有文件扩展名的链接为public目录下的静态文件
+	if IsStaticPath(target) {
+		if !gfile.Exists(filepath.Join(staticRoot, filepath.FromSlash(strings.TrimLeft(target, "/")))) {
+			if tag == "img" {
+				return BROKEN_IMAGE
+			}
+			return BROKEN_FILE
+		}
+		return ""
+	}
+	target = strings.Trim(target, "/")
+	if target == "" {
+		target = "index"
+	}
+	if !c.documentExists(target) {
+		return BROKEN_DOCUMENT
+	}
+	if anchor != "" {
+		if unescaped, err := url.PathUnescape(anchor); err == nil {
+			anchor = unescaped
+		}
+		if !c.getAnchors(c.resolvePath(target))[anchor] {
+			return BROKEN_ANCHOR
+		}
+	}
+	return ""
+}
+
+// 判断文档是否可以访问，包括通过别名跳转及未翻译时回退到默认语言的文档
+func (c *linkChecker) documentExists(path string) bool {
+	return c.resolvePath(path) != ""
+}
+
+// 获得链接实际显示的文档uri路径，文档不存在时返回空字符串
+func (c *linkChecker) resolvePath(path string) string {
+	if file := getFilePathByUri(path); gfile.Exists(file) {
+		if isHiddenDraft(gfcache.GetContents(file)) {
+			return ""
+		}
+		return path
+	}
+	if target := GetPathByAlias(path); target != "" {
+		return target
+	}
+	if target := GetFallbackPath(path); target != "" {
+		return c.resolvePath(target)
+	}
+	return ""
+}
+
+// 获得文档渲染后所有的锚点ID
+func (c *linkChecker) getAnchors(path string) map[string]bool {
+	if anchors, ok := c.anchors[path]; ok {
+		return anchors
+	}
+	anchors     := make(map[string]bool)
+	_, markdown := GetMarkdownWithMeta(path)
+	content, _  := ParseMarkdownWithToc(markdown)
+	match, _    := gregex.MatchAllString(checkIdPattern, content)
+	for _, m := range match {
+		anchors[html.UnescapeString(m[1])] = true
+	}
+	c.anchors[path] = anchors
+	return anchors
+}
@@ -0,0 +1,33 @@
+package lib_document
+
+import (
+	"github.com/gogf/gf/g/os/gfile"
+	"path/filepath"
+	"testing"
+)
+
+func TestCheckLink(t *testing.T) {
+	gfile.PutContents(filepath.Join(testDocRoot, "check", "快速开始.md"), "# 开始\n")
+	checker := &linkChecker{anchors: make(map[string]map[string]bool)}
+	cases := []struct {
+		link string
+		want string
+	}{
+		{"/index", ""},
+		{"/index#home", ""},
+		{"#home", ""},
+		{"/check/快速开始", ""},
+		{"/check/%E5%BF%AB%E9%80%9F%E5%BC%80%E5%A7%8B", ""},
+		{"/check/%E5%BF%AB%E9%80%9F%E5%BC%80%E5%A7%8B#%E5%BC%80%E5%A7%8B", ""},
+		{"/check/%E5%BF%AB%E9%80%9F%E5%BC%80%E5%A7%8B#nope", BROKEN_ANCHOR},
+		{"/check/missing", BROKEN_DOCUMENT},
+		{"/check/%ZZ", BROKEN_DOCUMENT},
+		{"https://goframe.org/missing", ""},
+		{"mailto:john@goframe.org", ""},
+	}
+	for _, c := range cases {
+		if got := checker.checkLink("index", "a", c.link); got != c.want {
+			t.Errorf("checkLink(%q) = %q, want %q", c.link, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package lib_document
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/gogf/gf/g"
+	"github.com/gogf/gf/g/os/gfile"
+	"github.com/gogf/gf/g/os/glog"
+	"github.com/gogf/gf/g/text/gstr"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+var (
+	// 文档路径不允许编辑(非法路径或者非当前版本的文档)
+	ErrInvalidEditPath = errors.New("invalid document path")
+	// 文档在加载之后已被修改
+	ErrConflict = errors.New("document has been changed since it was loaded")
+)
+
+// 文档编辑的提交者信息
+type Editor struct {
+	Name  string // 名称
+	Email string // 邮箱
+}
+
+// 获得文档markdown源文件的原始内容(包含front matter)及其内容哈希，文档不存在时exists为false、哈希为空字符串。
+// 内容直接从磁盘读取，不经过文件内容缓存，保证与保存时的校验一致
+func GetSource(path string) (content string, hash string, exists bool, err error) {
+	file, err := getEditFilePath(path)
+	if err != nil {
+		return "", "", false, err
+	}
+	return readSource(file)
+}
+
+// 保存文档markdown源文件，hash为加载时的内容哈希(新建文档为空字符串)，
+// 文件在加载之后被修改时返回ErrConflict(乐观并发控制)。
+// 开启editor.commit配置时，同时将变更提交到文档版本库，提交作者为编辑用户，开启editor.push配置时推送到远程分支，
+// 返回保存后的内容哈希
+func SaveSource(path string, content string, hash string, editor *Editor, message string) (string, error) {
+	file, err := getEditFilePath(path)
+	if err != nil {
+		return "", err
+	}
+	// 与文档版本库的更新过程互斥，避免保存时文件被git pull覆盖
+	updateMu.Lock()
+	defer updateMu.Unlock()
+	if _, current, _, err := readSource(file); err != nil {
+		return "", err
+	} else if current != hash {
+		return "", ErrConflict
+	}
+	// 浏览器表单提交的换行为CRLF，统一保存为LF
+	content = strings.Replace(content, "\r\n", "\n", -1)
+	if err := gfile.PutContents(file, content); err != nil {
+		return "", err
+	}
+	RefreshFiles([]string{file})
+	if g.Config().GetBool("editor.commit") {
+		if message == "" {
+			message = "Update " + strings.Trim(path, "/")
+		}
+		if err := commitSource(file, editor, message); err != nil {
+			glog.Cat("doc-edit").Printfln("doc %s saved but commit failed: %v", path, err)
+			return hashSource(content), err
+		}
+		// 推送失败(例如远程分支已更新)时提交保留在本地，下次更新文档版本库时变基后推送
+		if g.Config().GetBool("editor.push") {
+			if _, err := pushDocGit(getDocRoot()); err != nil {
+				glog.Cat("doc-edit").Printfln("doc %s committed but push failed: %v", path, err)
+				return hashSource(content), err
+			}
+		}
+	}
+	return hashSource(content), nil
+}
+
+// 获得可编辑文档的markdown文件绝对路径，只允许编辑当前版本文档目录下的文档，
+// 其他版本的worktree由版本引用自动检出，不允许直接修改
+func getEditFilePath(path string) (string, error) {
+	path = strings.Trim(path, "/")
+	if path == "" || gstr.Contains(path, "..") || strings.Contains(path, `\`) {
+		return "", ErrInvalidEditPath
+	}
+	if v, _ := getVersionByUri(path); v != getDefaultVersion() {
+		return "", ErrInvalidEditPath
+	}
+	return getFilePathByUri(path), nil
+}
+
+// 读取文件内容并计算内容哈希，文件不存在时不返回错误
+func readSource(file string) (content string, hash string, exists bool, err error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", "", false, nil
+		}
+		return "", "", false, err
+	}
+	content = string(data)
+	return content, hashSource(content), true, nil
+}
+
+// 文档内容哈希，用于判断文件在加载之后是否被修改
+func hashSource(content string) string {
+	sum := sha1.Sum([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// 将指定文件的变更提交到文档版本库，只提交该文件，不影响暂存区中的其他变更
+func commitSource(file string, editor *Editor, message string) error {
+	root     := getDocRoot()
+	relative := strings.TrimPrefix(strings.TrimPrefix(file, root), gfile.Separator)
+	if _, err := runGit(root, "add", "--", relative); err != nil {
+		return err
+	}
+	args := []string{"commit", "-m", message}
+	if editor != nil && editor.Name != "" {
+		// 去掉会破坏"名称 <邮箱>"格式的字符
+		clean := strings.NewReplacer("<", "", ">", "", "\n", "", "\r", "")
+		args = append(args, fmt.Sprintf("--author=%s <%s>", clean.Replace(editor.Name), clean.Replace(editor.Email)))
+	}
+	_, err := runGit(root, append(args, "--", relative)...)
+	return err
+}
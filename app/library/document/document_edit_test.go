@@ -0,0 +1,73 @@
+package lib_document
+
+import (
+	"github.com/gogf/gf/g/os/gfile"
+	"path/filepath"
+	"testing"
+)
+
+func TestGetEditFilePath(t *testing.T) {
+	cases := []struct {
+		path string
+		file string
+		err  error
+	}{
+		{"guide/start", filepath.Join(testDocRoot, "guide", "start.md"), nil},
+		{"/index/", filepath.Join(testDocRoot, "index.md"), nil},
+		{"", "", ErrInvalidEditPath},
+		{"../config", "", ErrInvalidEditPath},
+		{"guide/../../x", "", ErrInvalidEditPath},
+		{`guide\start`, "", ErrInvalidEditPath},
+	}
+	for _, c := range cases {
+		file, err := getEditFilePath(c.path)
+		if err != c.err || filepath.Clean(file) != filepath.Clean(c.file) {
+			t.Errorf("getEditFilePath(%q) = %q, %v, want %q, %v", c.path, file, err, c.file, c.err)
+		}
+	}
+}
+
+func TestSaveSource(t *testing.T) {
+	path := "edit-test"
+	file := filepath.Join(testDocRoot, path+".md")
+	defer gfile.Remove(file)
+
+	content, hash, exists, err := GetSource(path)
+	if err != nil || exists || content != "" || hash != "" {
+		t.Fatalf("GetSource(new) = %q, %q, %v, %v", content, hash, exists, err)
+	}
+	steps := []struct {
+		name    string
+		content string
+		hash    func() string
+		err     error
+	}{
+		{"create", "# V1\r\n", func() string { return "" }, nil},
+		{"create again", "# V1b\n", func() string { return "" }, ErrConflict},
+		{"stale hash", "# V2\n", func() string { return hashSource("# other\n") }, ErrConflict},
+		{"current hash", "# V2\n", func() string { return hashSource("# V1\n") }, nil},
+		{"old hash", "# V3\n", func() string { return hashSource("# V1\n") }, ErrConflict},
+	}
+	for _, s := range steps {
+		saved, err := SaveSource(path, s.content, s.hash(), nil, "")
+		if err != s.err {
+			t.Fatalf("%s: err = %v, want %v", s.name, err, s.err)
+		}
+		if err != nil {
+			if saved != "" {
+				t.Errorf("%s: hash = %q, want empty", s.name, saved)
+			}
+			continue
+		}
+		content, hash, exists, _ := GetSource(path)
+		if !exists || hash != saved || hash != hashSource(content) {
+			t.Errorf("%s: GetSource = %q, %q, %v, saved hash %q", s.name, content, hash, exists, saved)
+		}
+	}
+	if content, _, _, _ := GetSource(path); content != "# V2\n" {
+		t.Errorf("final content = %q, want %q", content, "# V2\n")
+	}
+	if _, err := SaveSource("../escape", "x", "", nil, ""); err != ErrInvalidEditPath {
+		t.Errorf("SaveSource(../escape) err = %v, want %v", err, ErrInvalidEditPath)
+	}
+}
@@ -0,0 +1,142 @@
+package lib_document
+
+import (
+	"fmt"
+	"github.com/gogf/gf/g/os/gfcache"
+	"github.com/gogf/gf/g/os/gfile"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// 摘要分隔标记，标记之前的内容作为摘要
+	summaryMarker = "<!--more-->"
+	// 没有摘要分隔标记时，作为摘要的段落数量
+	summaryBlocks = 2
+)
+
+// 最近更新的文档
+type RecentDocument struct {
+	Path    string    `json:"path"`    // 文档uri路径(不包含前导"/")
+	Title   string    `json:"title"`   // 文档标题
+	Author  string    `json:"author"`  // 作者
+	Summary string    `json:"summary"` // HTML格式的摘要
+	Date    time.Time `json:"date"`    // 发布时间，front matter中未设置date时为更新时间
+	Updated time.Time `json:"updated"` // 更新时间，优先使用git提交时间，否则为文件修改时间
+}
+
+// 获得当前版本中最近更新的文档列表，section为目录前缀(为空表示所有文档)，按照更新时间从新到旧排序。
+// 先按照更新时间排序，只为前limit个文档渲染摘要，结果缓存到文档变更(RefreshFiles)时，调用端不能修改返回的列表。
+// section来自请求路径，空结果(目录不存在等)只缓存较短的时间，避免任意路径的请求长期占用缓存
+func GetRecentDocuments(section string, limit int) []*RecentDocument {
+	section = strings.Trim(section, "/")
+	v      := getDefaultVersion()
+	key    := fmt.Sprintf("doc_recent_%s_%d", section, limit)
+	if cached := v.cache.Get(key); cached != nil {
+		return cached.([]*RecentDocument)
+	}
+	docs := loadRecentDocuments(v, section, limit)
+	if len(docs) == 0 {
+		v.cache.Set(key, docs, negativeCacheExpire)
+	} else {
+		v.cache.Set(key, docs, 0)
+	}
+	return docs
+}
+
+// 检索版本中最近更新的文档列表
+func loadRecentDocuments(v *Version, section string, limit int) []*RecentDocument {
+	lang     := GetPathLanguage(section)
+	docs     := make([]*RecentDocument, 0)
+	contents := make(map[*RecentDocument]string)
+	for _, file := range v.getMdFiles() {
+		path := strings.TrimLeft(getUriByFilePath(file), "/")
+		if path == GetMenuPath(path) || GetPathLanguage(path) != lang {
+			continue
+		}
+		if section != "" && path != section && !strings.HasPrefix(path, section+"/") {
+			continue
+		}
+		meta, markdown := ParseFrontMatter(gfcache.GetContents(file))
+		if markdown == "" || meta.Draft {
+			continue
+		}
+		doc := &RecentDocument{
+			Path:    path,
+			Title:   meta.Title,
+			Author:  meta.Author,
+			Date:    meta.Date,
+			Updated: GetUpdatedTime(path),
+		}
+		contents[doc] = markdown
+		docs = append(docs, doc)
+	}
+	sort.SliceStable(docs, func(i, j int) bool {
+		return docs[i].Updated.After(docs[j].Updated)
+	})
+	if limit > 0 && len(docs) > limit {
+		docs = docs[:limit]
+	}
+	// 摘要渲染及标题查找只针对返回的文档
+	for _, doc := range docs {
+		doc.Summary = ParseMarkdown(getSummary(contents[doc]))
+		if doc.Title == "" {
+			doc.Title = GetTitleByPath(doc.Path)
+		}
+		if doc.Title == "" {
+			doc.Title = doc.Path
+		}
+		if doc.Date.IsZero() {
+			doc.Date = doc.Updated
+		}
+	}
+	return docs
+}
+
+// 获得文档的更新时间，优先使用git最后一次提交的时间，文件未提交时使用文件修改时间
+func GetUpdatedTime(path string) time.Time {
+	v, _ := getVersionByUri(path)
+	file := getFilePathByUri(path)
+	times := v.cache.GetOrSetFunc("doc_commit_times", func() interface{} {
+		times, _ := getCommitTimes(v.root)
+		if times == nil {
+			times = make(map[string]time.Time)
+		}
+		return times
+	}, 0).(map[string]time.Time)
+	if t, ok := times[file]; ok {
+		return t
+	}
+	if info, err := gfile.Stat(file); err == nil {
+		return info.ModTime()
+	}
+	return time.Time{}
+}
+
+// 获得markdown内容的摘要：摘要分隔标记之前的内容，没有标记时为开头的若干段落(不包含一级标题)
+func getSummary(markdown string) string {
+	if i := strings.Index(markdown, summaryMarker); i >= 0 {
+		return markdown[:i]
+	}
+	blocks := make([]string, 0, summaryBlocks)
+	fence  := false
+	for _, block := range strings.Split(strings.Replace(markdown, "\r\n", "\n", -1), "\n\n") {
+		trimmed := strings.TrimSpace(block)
+		if trimmed == "" {
+			continue
+		}
+		// 代码块中可能包含空行，保持代码块完整
+		if strings.Count(trimmed, "```")%2 == 1 {
+			fence = !fence
+		}
+		if len(blocks) == 0 && strings.HasPrefix(trimmed, "# ") {
+			continue
+		}
+		blocks = append(blocks, block)
+		if !fence && len(blocks) >= summaryBlocks {
+			break
+		}
+	}
+	return strings.Join(blocks, "\n\n")
+}
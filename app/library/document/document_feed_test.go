@@ -0,0 +1,45 @@
+package lib_document
+
+import (
+	"github.com/gogf/gf/g/os/gfile"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestGetRecentDocuments(t *testing.T) {
+	files := map[string]string{
+		"feed/a.md":     "---\ntitle: A\n---\nfirst paragraph\n",
+		"feed/b.md":     "---\ndraft: true\n---\nhidden\n",
+		"feed/sub/c.md": "# C\n\nbody\n",
+	}
+	paths := make([]string, 0)
+	for name, content := range files {
+		path := filepath.Join(testDocRoot, filepath.FromSlash(name))
+		gfile.PutContents(path, content)
+		paths = append(paths, path)
+	}
+	RefreshFiles(paths)
+	cases := []struct {
+		section string
+		want    []string
+	}{
+		{"feed", []string{"feed/a", "feed/sub/c"}},
+		{"/feed/sub/", []string{"feed/sub/c"}},
+		{"missing", []string{}},
+	}
+	for _, c := range cases {
+		got := make([]string, 0)
+		for _, doc := range GetRecentDocuments(c.section, 10) {
+			got = append(got, doc.Path)
+		}
+		sort.Strings(got)
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("GetRecentDocuments(%q) = %q, want %q", c.section, got, c.want)
+		}
+	}
+	if docs := GetRecentDocuments("feed", 1); len(docs) != 1 || docs[0].Summary == "" {
+		t.Errorf("GetRecentDocuments limit 1 = %v", docs)
+	}
+}
@@ -0,0 +1,141 @@
+package lib_document
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/gogf/gf/g"
+	"github.com/gogf/gf/g/os/gfile"
+	"github.com/gogf/gf/g/util/gconv"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// 获得文档版本库的远程仓库名称，默认为origin
+func getDocRemote() string {
+	if remote := g.Config().GetString("document.remote"); remote != "" {
+		return remote
+	}
+	return "origin"
+}
+
+// 获得文档版本库跟踪的分支名称，默认为master
+func GetDocBranch() string {
+	if branch := g.Config().GetString("document.branch"); branch != "" {
+		return branch
+	}
+	return "master"
+}
+
+// 拉取文档版本库的远程分支更新。在线编辑开启editor.commit时本地可能存在尚未推送的提交，
+// 此时将本地提交变基到远程分支之后(push为true时同时推送)，变基冲突时放弃变基并返回错误，需要人工处理
+func pullDocGit(root string, push bool) (string, error) {
+	remote := getDocRemote()
+	branch := GetDocBranch()
+	if _, err := runGit(root, "fetch", remote, branch); err != nil {
+		return "", err
+	}
+	ahead, err := runGit(root, "rev-list", "--count", "FETCH_HEAD..HEAD")
+	if err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(ahead) == "0" {
+		return runGit(root, "merge", "--ff-only", "FETCH_HEAD")
+	}
+	output, err := runGit(root, "rebase", "--autostash", "FETCH_HEAD")
+	if err != nil {
+		runGit(root, "rebase", "--abort")
+		return output, fmt.Errorf("local commits diverged from %s/%s: %v", remote, branch, err)
+	}
+	if push {
+		if _, err := pushDocGit(root); err != nil {
+			return output, err
+		}
+	}
+	return output, nil
+}
+
+// 将本地提交推送到文档版本库的远程分支
+func pushDocGit(root string) (string, error) {
+	return runGit(root, "push", getDocRemote(), "HEAD:"+GetDocBranch())
+}
+
+// 在指定目录下执行git命令，参数直接传递给git进程而不经过shell解析，返回标准输出内容
+func runGit(dir string, args ...string) (string, error) {
+	stdout := bytes.NewBuffer(nil)
+	stderr := bytes.NewBuffer(nil)
+	cmd    := exec.Command("git", args...)
+	cmd.Dir    = dir
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	if err := cmd.Run(); err != nil {
+		return stdout.String(), fmt.Errorf("git %s: %v: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
+
+// 获得指定目录下文档版本库当前的HEAD提交，获取失败时返回空字符串
+func getHead(dir string) string {
+	head, err := runGit(dir, "rev-parse", "HEAD")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(head)
+}
+
+// 获得指定目录下两个提交之间变更文件的绝对路径列表，重命名的文件同时包含新旧路径
+func getChangedFiles(dir string, from, to string) ([]string, error) {
+	if from == "" || to == "" {
+		return nil, errors.New("empty commit")
+	}
+	output, err := runGit(dir, "-c", "core.quotepath=off", "diff", "--name-status", "-M", "--relative", from, to)
+	if err != nil {
+		return nil, err
+	}
+	files := make([]string, 0)
+	for _, line := range strings.Split(output, "\n") {
+		// 格式: 状态\t路径[\t新路径]
+		fields := strings.Split(strings.TrimSpace(line), "\t")
+		for _, path := range fields[1:] {
+			files = append(files, dir+gfile.Separator+filepath.FromSlash(path))
+		}
+	}
+	return files, nil
+}
+
+// 获得指定目录下所有文件最后一次提交的时间，键名为文件绝对路径
+func getCommitTimes(dir string) (map[string]time.Time, error) {
+	output, err := runGit(dir, "-c", "core.quotepath=off", "log", "--format=%x1e%ct", "--name-only", "--relative", "--no-renames")
+	if err != nil {
+		return nil, err
+	}
+	times := make(map[string]time.Time)
+	// 格式: \x1e提交时间戳\n\n文件路径列表，提交记录按照时间从新到旧排列
+	for _, commit := range strings.Split(output, "\x1e") {
+		lines := strings.Split(strings.TrimSpace(commit), "\n")
+		if len(lines) < 2 {
+			continue
+		}
+		t := time.Unix(gconv.Int64(lines[0]), 0)
+		for _, line := range lines[1:] {
+			if line = strings.TrimSpace(line); line == "" {
+				continue
+			}
+			path := dir + gfile.Separator + filepath.FromSlash(line)
+			if _, ok := times[path]; !ok {
+				times[path] = t
+			}
+		}
+	}
+	return times, nil
+}
+
+// 获得提交的短哈希
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
@@ -0,0 +1,86 @@
+package lib_document
+
+import (
+	"github.com/gogf/gf/g/os/gfile"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// 在指定目录下执行git命令，失败时结束测试
+func mustGit(t *testing.T, dir string, args ...string) string {
+	output, err := runGit(dir, args...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.TrimSpace(output)
+}
+
+// 提交文件到指定的版本库
+func commitFile(t *testing.T, dir string, name string, content string) {
+	gfile.PutContents(filepath.Join(dir, name), content)
+	mustGit(t, dir, "add", name)
+	mustGit(t, dir, "-c", "user.name=test", "-c", "user.email=test@goframe.org", "commit", "-q", "-m", "update "+name)
+}
+
+func TestPullDocGit(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gf-blog-git")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	remote := filepath.Join(dir, "remote.git")
+	local  := filepath.Join(dir, "local")
+	other  := filepath.Join(dir, "other")
+	mustGit(t, dir, "init", "-q", "--bare", "--initial-branch=master", remote)
+	mustGit(t, dir, "clone", "-q", remote, other)
+	mustGit(t, other, "checkout", "-q", "-b", "master")
+	commitFile(t, other, "index.md", "# Home\n")
+	mustGit(t, other, "push", "-q", "origin", "master")
+	mustGit(t, dir, "clone", "-q", remote, local)
+	// 开启在线编辑提交时，文档版本库需要配置提交者信息，变基时同样需要
+	mustGit(t, local, "config", "user.name", "test")
+	mustGit(t, local, "config", "user.email", "test@goframe.org")
+
+	// 没有本地提交时快进合并
+	commitFile(t, other, "a.md", "a\n")
+	mustGit(t, other, "push", "-q", "origin", "master")
+	if _, err := pullDocGit(local, true); err != nil {
+		t.Fatalf("fast-forward pull: %v", err)
+	}
+	if !gfile.Exists(filepath.Join(local, "a.md")) {
+		t.Errorf("fast-forward pull did not update a.md")
+	}
+
+	// 本地提交(在线编辑)与远程更新分叉时，变基后推送
+	commitFile(t, local, "edit.md", "edited\n")
+	commitFile(t, other, "b.md", "b\n")
+	mustGit(t, other, "push", "-q", "origin", "master")
+	if _, err := pullDocGit(local, true); err != nil {
+		t.Fatalf("diverged pull: %v", err)
+	}
+	if !gfile.Exists(filepath.Join(local, "b.md")) || !gfile.Exists(filepath.Join(local, "edit.md")) {
+		t.Errorf("diverged pull did not keep both changes")
+	}
+	if head, remoteHead := mustGit(t, local, "rev-parse", "HEAD"), mustGit(t, remote, "rev-parse", "master"); head != remoteHead {
+		t.Errorf("local commit not pushed: HEAD %s, remote %s", head, remoteHead)
+	}
+
+	// 变基冲突时放弃变基，保留本地提交并返回错误
+	commitFile(t, local, "index.md", "# Local\n")
+	commitFile(t, other, "index.md", "# Remote\n")
+	mustGit(t, other, "-c", "user.name=test", "-c", "user.email=test@goframe.org", "pull", "-q", "--rebase", "origin", "master")
+	mustGit(t, other, "push", "-q", "origin", "master")
+	head := mustGit(t, local, "rev-parse", "HEAD")
+	if _, err := pullDocGit(local, true); err == nil {
+		t.Errorf("conflicting pull should fail")
+	}
+	if got := mustGit(t, local, "rev-parse", "HEAD"); got != head {
+		t.Errorf("conflicting pull changed HEAD from %s to %s", head, got)
+	}
+	if gfile.Exists(filepath.Join(local, ".git", "rebase-merge")) {
+		t.Errorf("conflicting pull left a rebase in progress")
+	}
+}
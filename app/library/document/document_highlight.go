@@ -0,0 +1,72 @@
+package lib_document
+
+import (
+	"gf-blog/app/library/highlight"
+	"github.com/gogf/gf/g"
+	"github.com/gogf/gf/g/text/gregex"
+	"github.com/russross/blackfriday"
+	"io"
+	"strings"
+)
+
+// 代码块信息字符串格式：```go {3-5} 或者 ```go{3-5,linenos}
+const fenceInfoPattern = "^(\\s{0,3}(?:`{3,}|~{3,}))[ \\t]*([\\w+#.-]*)[ \\t]+\\{([^}\\n]*)\\}[ \\t]*$"
+
+// 带有代码语法高亮的markdown渲染器
+type highlightRenderer struct {
+	*blackfriday.HTMLRenderer
+	lineNumbers bool // 默认是否显示行号
+}
+
+// 创建markdown渲染器
+func newHighlightRenderer() *highlightRenderer {
+	return &highlightRenderer{
+		HTMLRenderer: blackfriday.NewHTMLRenderer(blackfriday.HTMLRendererParameters{
+			Flags: blackfriday.CommonHTMLFlags,
+		}),
+		lineNumbers: g.Config().GetBool("highlight.lineNumbers"),
+	}
+}
+
+// 对支持的语言代码块进行语法高亮，其他节点交由blackfriday默认渲染
+func (r *highlightRenderer) RenderNode(w io.Writer, node *blackfriday.Node, entering bool) blackfriday.WalkStatus {
+	if node.Type == blackfriday.CodeBlock && len(node.Info) > 0 {
+		lang, options := lib_highlight.ParseInfo(string(node.Info))
+		if lib_highlight.Supported(lang) || len(options.Highlight) > 0 || options.LineNumbers {
+			if r.lineNumbers {
+				options.LineNumbers = true
+			}
+			io.WriteString(w, lib_highlight.Render(string(node.Literal), lang, options))
+			return blackfriday.GoToNext
+		}
+	}
+	return r.HTMLRenderer.RenderNode(w, node, entering)
+}
+
+// blackfriday只识别不包含空格的代码块信息字符串，
+// 这里将 ```go {3-5} 形式的代码块起始行规范为 ```go{3-5}，代码块内的内容保持不变
+func normalizeFenceInfo(content string) string {
+	if !strings.Contains(content, "{") {
+		return content
+	}
+	lines := strings.Split(content, "\n")
+	fence := ""
+	for i, line := range lines {
+		trimmed := strings.TrimLeft(line, " ")
+		if fence != "" {
+			// 代码块结束行
+			if strings.HasPrefix(trimmed, fence) && strings.TrimSpace(strings.TrimLeft(trimmed, fence[:1])) == "" {
+				fence = ""
+			}
+			continue
+		}
+		if !strings.HasPrefix(trimmed, "```") && !strings.HasPrefix(trimmed, "~~~") {
+			continue
+		}
+		fence = trimmed[:len(trimmed)-len(strings.TrimLeft(trimmed, trimmed[:1]))]
+		if match, _ := gregex.MatchString(fenceInfoPattern, line); len(match) > 3 {
+			lines[i] = match[1] + match[2] + "{" + strings.Join(strings.Fields(strings.Replace(match[3], ",", " ", -1)), ",") + "}"
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,178 @@
+package lib_document
+
+import (
+	"errors"
+	"github.com/gogf/gf/g/os/gfile"
+	"github.com/gogf/gf/g/text/gregex"
+	"github.com/gogf/gf/g/util/gconv"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// 文档修订版本(git提交记录)
+type Revision struct {
+	Hash    string    `json:"hash"`    // 提交哈希
+	Short   string    `json:"short"`   // 提交短哈希
+	Author  string    `json:"author"`  // 作者名称
+	Email   string    `json:"email"`   // 作者邮箱
+	Date    time.Time `json:"date"`    // 提交时间
+	Subject string    `json:"subject"` // 提交说明
+}
+
+// 文档贡献者
+type Contributor struct {
+	Name    string `json:"name"`    // 名称
+	Email   string `json:"email"`   // 邮箱
+	Commits int    `json:"commits"` // 提交次数
+}
+
+// 文档的版本历史
+type History struct {
+	Updated      time.Time      `json:"updated"`      // 最后修改时间
+	Author       string         `json:"author"`       // 最后修改的作者
+	Contributors []*Contributor `json:"contributors"` // 贡献者列表，按照提交次数从多到少排序
+	Revisions    []*Revision    `json:"-"`            // 所有修订版本，按照时间从新到旧排序
+}
+
+// 非法的提交哈希，只允许十六进制的提交哈希，防止将其他内容作为git参数
+var ErrInvalidRevision = errors.New("invalid revision")
+
+// 获得文档的版本历史，文档不存在或者没有提交记录时返回的历史为空。
+// 文档不存在时不执行git命令也不缓存，没有提交记录时只缓存较短的时间
+func GetHistory(path string) *History {
+	path  = strings.Trim(path, "/")
+	v, _ := getVersionByUri(path)
+	if !gfile.Exists(getFilePathByUri(path)) {
+		return newHistory()
+	}
+	key := "doc_history_" + path
+	if cached := v.cache.Get(key); cached != nil {
+		return cached.(*History)
+	}
+	history := loadHistory(v, path)
+	if len(history.Revisions) == 0 {
+		v.cache.Set(key, history, negativeCacheExpire)
+	} else {
+		v.cache.Set(key, history, 0)
+	}
+	return history
+}
+
+// 创建空的版本历史
+func newHistory() *History {
+	return &History{
+		Contributors: make([]*Contributor, 0),
+		Revisions:    make([]*Revision, 0),
+	}
+}
+
+// 通过git log获得文档的版本历史
+func loadHistory(v *Version, path string) *History {
+	history := newHistory()
+	output, err := runGit(v.root, "log", "--follow", "--format=%H%x1f%an%x1f%ae%x1f%ct%x1f%s", "--", getRelativeFilePath(path))
+	if err != nil {
+		return history
+	}
+	contributors := make(map[string]*Contributor)
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		fields := strings.Split(line, "\x1f")
+		if len(fields) != 5 {
+			continue
+		}
+		revision := &Revision{
+			Hash:    fields[0],
+			Short:   shortHash(fields[0]),
+			Author:  fields[1],
+			Email:   fields[2],
+			Date:    time.Unix(gconv.Int64(fields[3]), 0),
+			Subject: fields[4],
+		}
+		history.Revisions = append(history.Revisions, revision)
+		key := strings.ToLower(revision.Email)
+		if c, ok := contributors[key]; ok {
+			c.Commits++
+		} else {
+			contributors[key] = &Contributor{Name: revision.Author, Email: revision.Email, Commits: 1}
+			history.Contributors = append(history.Contributors, contributors[key])
+		}
+	}
+	sort.SliceStable(history.Contributors, func(i, j int) bool {
+		return history.Contributors[i].Commits > history.Contributors[j].Commits
+	})
+	if len(history.Revisions) > 0 {
+		history.Updated = history.Revisions[0].Date
+		history.Author  = history.Revisions[0].Author
+	}
+	return history
+}
+
+// 分页获得文档的修订版本列表，page从1开始，同时返回修订版本总数
+func GetRevisions(path string, page int, size int) ([]*Revision, int) {
+	revisions := GetHistory(path).Revisions
+	total     := len(revisions)
+	if page < 1 {
+		page = 1
+	}
+	start := (page - 1) * size
+	end   := start + size
+	if start > total {
+		start = total
+	}
+	if end > total {
+		end = total
+	}
+	return revisions[start:end], total
+}
+
+// 获得文档在指定修订版本的markdown内容(包含front matter)
+func GetRevisionMarkdown(path string, hash string) (string, error) {
+	if !isRevisionHash(hash) {
+		return "", ErrInvalidRevision
+	}
+	v, _ := getVersionByUri(path)
+	// 文档可能被重命名，需要使用该修订版本中的文件路径
+	file := getRevisionFilePath(path, hash)
+	return runGit(v.root, "show", hash+":./"+file)
+}
+
+// 获得文档在两个修订版本之间的统一格式差异(unified diff)
+func GetDiff(path string, from string, to string) (string, error) {
+	if !isRevisionHash(from) || !isRevisionHash(to) {
+		return "", ErrInvalidRevision
+	}
+	v, _ := getVersionByUri(path)
+	return runGit(v.root, "-c", "core.quotepath=off", "diff", "--find-renames", from, to, "--", getRevisionFilePath(path, from), getRelativeFilePath(path))
+}
+
+// 获得文档相对于所属版本文档目录的文件路径(使用"/"分隔)
+func getRelativeFilePath(path string) string {
+	v, _ := getVersionByUri(path)
+	rel, _ := filepath.Rel(v.root, getFilePathByUri(path))
+	return filepath.ToSlash(rel)
+}
+
+// 获得文档在指定修订版本中的文件路径，文档被重命名时返回重命名之前的路径
+func getRevisionFilePath(path string, hash string) string {
+	v, _    := getVersionByUri(path)
+	current := getRelativeFilePath(path)
+	// git输出的提交哈希为小写
+	hash     = strings.ToLower(hash)
+	output, err := runGit(v.root, "-c", "core.quotepath=off", "log", "--follow", "--name-only", "--relative", "--format=%x1e%H", "--", current)
+	if err != nil {
+		return current
+	}
+	for _, commit := range strings.Split(output, "\x1e") {
+		lines := strings.Split(strings.TrimSpace(commit), "\n")
+		if len(lines) >= 2 && strings.HasPrefix(lines[0], hash) {
+			return strings.TrimSpace(lines[len(lines)-1])
+		}
+	}
+	return current
+}
+
+// 判断是否为合法的提交哈希(4-40位十六进制字符)
+func isRevisionHash(hash string) bool {
+	return gregex.IsMatchString(`^[0-9a-fA-F]{4,40}$`, hash)
+}
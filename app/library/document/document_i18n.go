@@ -0,0 +1,203 @@
+package lib_document
+
+import (
+	"gf-blog/app/library/search"
+	"github.com/gogf/gf/g"
+	"github.com/gogf/gf/g/os/gfile"
+	"github.com/gogf/gf/g/util/gconv"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// 文档语言，默认语言的文档位于文档目录根目录，其他语言位于对应的子目录
+type Language struct {
+	Code string `json:"code"` // 语言代码(BCP 47)，例如: zh-CN、en
+	Name string `json:"name"` // 语言显示名称
+	Dir  string `json:"dir"`  // 文档子目录，同时作为文档uri路径前缀，默认语言为空
+}
+
+// 语言切换链接
+type LanguageLink struct {
+	Code    string `json:"code"`    // 语言代码
+	Name    string `json:"name"`    // 语言显示名称
+	Path    string `json:"path"`    // 同一文档在该语言下的uri路径(不包含前导"/")
+	Current bool   `json:"current"` // 是否为当前访问的语言
+	Exists  bool   `json:"exists"`  // 该语言的翻译文档是否存在
+}
+
+var (
+	// 语言列表，第一项为默认语言
+	languages     []*Language
+	languagesOnce sync.Once
+)
+
+// 获得所有文档语言，第一项为默认语言
+func GetLanguages() []*Language {
+	languagesOnce.Do(func() {
+		c    := g.Config()
+		code := c.GetString("i18n.default")
+		if code == "" {
+			code = "zh-CN"
+		}
+		name := c.GetString("i18n.name")
+		if name == "" {
+			name = code
+		}
+		languages = []*Language{{Code: code, Name: name}}
+		for _, item := range c.GetArray("i18n.languages") {
+			m    := gconv.Map(item)
+			lang := &Language{
+				Code: gconv.String(m["code"]),
+				Name: gconv.String(m["name"]),
+				Dir:  strings.Trim(gconv.String(m["dir"]), "/"),
+			}
+			if lang.Code == "" {
+				continue
+			}
+			if lang.Name == "" {
+				lang.Name = lang.Code
+			}
+			if lang.Dir == "" {
+				lang.Dir = strings.ToLower(lang.Code)
+			}
+			languages = append(languages, lang)
+		}
+	})
+	return languages
+}
+
+// 获得默认语言
+func getDefaultLanguage() *Language {
+	return GetLanguages()[0]
+}
+
+// 根据语言代码获得语言(忽略大小写)，不存在时返回nil
+func GetLanguage(code string) *Language {
+	for _, lang := range GetLanguages() {
+		if strings.EqualFold(lang.Code, code) {
+			return lang
+		}
+	}
+	return nil
+}
+
+// 根据Accept-Language请求头匹配优先级最高的文档语言，无法匹配时返回nil，
+// 例如: en-US,en;q=0.9,zh-CN;q=0.8
+func MatchLanguage(accept string) *Language {
+	type acceptItem struct {
+		code string
+		q    float64
+	}
+	items := make([]acceptItem, 0)
+	for _, part := range strings.Split(accept, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		item   := acceptItem{code: strings.TrimSpace(fields[0]), q: 1}
+		for _, field := range fields[1:] {
+			if field = strings.TrimSpace(field); strings.HasPrefix(field, "q=") {
+				item.q = gconv.Float64(field[2:])
+			}
+		}
+		if item.code != "" && item.code != "*" && item.q > 0 {
+			items = append(items, item)
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].q > items[j].q
+	})
+	for _, item := range items {
+		if lang := GetLanguage(item.code); lang != nil {
+			return lang
+		}
+		// 只匹配主语言标签，例如: en-US => en
+		primary := strings.SplitN(item.code, "-", 2)[0]
+		for _, lang := range GetLanguages() {
+			if strings.EqualFold(strings.SplitN(lang.Code, "-", 2)[0], primary) {
+				return lang
+			}
+		}
+	}
+	return nil
+}
+
+// 语言的文档uri路径前缀
+func (l *Language) prefix() string {
+	if l.Dir == "" {
+		return ""
+	}
+	return l.Dir + "/"
+}
+
+// 根据文档uri路径获得所属版本、语言以及语言内的相对路径
+func getLanguageByUri(uri string) (*Version, *Language, string) {
+	v, path := getVersionByUri(uri)
+	for _, lang := range GetLanguages()[1:] {
+		if path == lang.Dir {
+			return v, lang, "index"
+		}
+		if strings.HasPrefix(path, lang.Dir+"/") {
+			return v, lang, path[len(lang.Dir)+1:]
+		}
+	}
+	return v, getDefaultLanguage(), path
+}
+
+// 获得文档uri路径所属的语言
+func GetPathLanguage(path string) *Language {
+	_, lang, _ := getLanguageByUri(path)
+	return lang
+}
+
+// 获得同一文档在指定语言下的uri路径(不包含前导"/")，语言不存在时返回原路径
+func GetLanguagePath(path string, code string) string {
+	lang := GetLanguage(code)
+	if lang == nil {
+		return strings.Trim(path, "/")
+	}
+	v, _, rest := getLanguageByUri(path)
+	return v.prefix() + lang.prefix() + rest
+}
+
+// 获得未翻译文档回退到默认语言的uri路径，已经是默认语言时返回空字符串
+func GetFallbackPath(path string) string {
+	v, lang, rest := getLanguageByUri(path)
+	if lang == getDefaultLanguage() {
+		return ""
+	}
+	return v.prefix() + rest
+}
+
+// 获得同一文档在各个语言下的切换链接，只有默认语言时返回空列表
+func GetLanguageLinks(path string) []LanguageLink {
+	links := make([]LanguageLink, 0)
+	if len(GetLanguages()) < 2 {
+		return links
+	}
+	v, current, rest := getLanguageByUri(path)
+	for _, lang := range GetLanguages() {
+		link := LanguageLink{
+			Code:    lang.Code,
+			Name:    lang.Name,
+			Path:    v.prefix() + lang.prefix() + rest,
+			Current: lang == current,
+		}
+		link.Exists = gfile.Exists(getFilePathByUri(link.Path))
+		links = append(links, link)
+	}
+	return links
+}
+
+// 过滤检索结果，只保留指定语言的文档
+func FilterByLanguage(results []lib_search.Result, code string) []lib_search.Result {
+	lang := GetLanguage(code)
+	if lang == nil || len(GetLanguages()) < 2 {
+		return results
+	}
+	filtered := make([]lib_search.Result, 0, len(results))
+	for _, result := range results {
+		if _, l, _ := getLanguageByUri(result.Path); l == lang {
+			filtered = append(filtered, result)
+		}
+	}
+	return filtered
+}
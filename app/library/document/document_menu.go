@@ -0,0 +1,241 @@
+package lib_document
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/gogf/gf/g/encoding/ghtml"
+	"github.com/gogf/gf/g/text/gregex"
+	"strings"
+)
+
+const (
+	// 菜单列表项格式：* [标题](路径) 或者 * 分组标题
+	menuItemPattern = `^(\s*)[*+-]\s+(?:\[(.+?)\]\((.*?)\)|(.+?))\s*$`
+)
+
+// 导航菜单节点
+type MenuNode struct {
+	Title    string      // 菜单标题
+	Path     string      // 文档uri路径(不包含前导"/")，外部链接保持原样，分组节点为空
+	Depth    int         // 层级深度，顶级节点为0
+	Children []*MenuNode // 下级节点
+	Parent   *MenuNode   // 上级节点，顶级节点为nil
+}
+
+// 导航菜单树，根据menus.md文档构建
+type Menu struct {
+	Nodes []*MenuNode          // 顶级节点列表
+	pages []*MenuNode          // 按照菜单顺序排列的文档节点列表
+	paths map[string]*MenuNode // 文档路径 => 节点(同一文档多次出现时以第一次为准)
+}
+
+// 获得文档路径所属版本及语言的导航菜单树，只在menus.md变更时重新构建
+func GetMenu(path string) *Menu {
+	v, lang, _ := getLanguageByUri(path)
+	return v.cache.GetOrSetFunc("doc_menu_" + lang.Code, func() interface{} {
+		prefix := v.prefix() + lang.prefix()
+		return parseMenu(GetMarkdown(prefix+"menus"), prefix)
+	}, 0).(*Menu)
+}
+
+// 获得文档路径所属版本及语言的菜单文档uri路径，例如: v1.5/en/menus
+func GetMenuPath(path string) string {
+	v, lang, _ := getLanguageByUri(path)
+	return v.prefix() + lang.prefix() + "menus"
+}
+
+// 解析菜单markdown内容为导航菜单树，根据列表项的缩进确定层级关系
+func ParseMenu(content string) *Menu {
+	return parseMenu(content, "")
+}
+
+// 解析菜单markdown内容，站内文档路径添加指定的版本前缀
+func parseMenu(content string, prefix string) *Menu {
+	type stackItem struct {
+		indent int
+		node   *MenuNode
+	}
+	menu := &Menu{
+		Nodes: make([]*MenuNode, 0),
+		pages: make([]*MenuNode, 0),
+		paths: make(map[string]*MenuNode),
+	}
+	stack := make([]stackItem, 0)
+	for _, line := range strings.Split(content, "\n") {
+		match, _ := gregex.MatchString(menuItemPattern, strings.Replace(line, "\t", "    ", -1))
+		if len(match) != 5 {
+			continue
+		}
+		node := &MenuNode{
+			Title:    match[2],
+			Path:     getMenuPath(match[3]),
+			Children: make([]*MenuNode, 0),
+		}
+		if match[2] == "" {
+			node.Title = match[4]
+		}
+		if node.IsDocument() {
+			node.Path = prefix + node.Path
+		}
+		indent := len(match[1])
+		for len(stack) > 0 && stack[len(stack)-1].indent >= indent {
+			stack = stack[:len(stack)-1]
+		}
+		if len(stack) == 0 {
+			menu.Nodes = append(menu.Nodes, node)
+		} else {
+			node.Parent   = stack[len(stack)-1].node
+			node.Depth    = node.Parent.Depth + 1
+			node.Parent.Children = append(node.Parent.Children, node)
+		}
+		stack = append(stack, stackItem{indent, node})
+		if node.IsDocument() {
+			if _, ok := menu.paths[node.Path]; !ok {
+				menu.paths[node.Path] = node
+				menu.pages = append(menu.pages, node)
+			}
+		}
+	}
+	return menu
+}
+
+// 将菜单链接转换为文档uri路径，例如: ./net/ghttp.md#route => net/ghttp
+func getMenuPath(link string) string {
+	link = strings.TrimSpace(link)
+	if link == "" || strings.Contains(link, "://") || strings.HasPrefix(link, "mailto:") {
+		return link
+	}
+	if i := strings.IndexByte(link, '#'); i >= 0 {
+		link = link[:i]
+	}
+	link = strings.TrimSuffix(link, ".md")
+	link = strings.TrimPrefix(link, "./")
+	return strings.Trim(link, "/")
+}
+
+// 根据文档路径查找菜单节点，不存在时返回nil
+func (m *Menu) Find(path string) *MenuNode {
+	v, lang, rest := getLanguageByUri(path)
+	return m.paths[v.prefix()+lang.prefix()+rest]
+}
+
+// 按照菜单顺序排列的文档节点列表
+func (m *Menu) Pages() []*MenuNode {
+	return m.pages
+}
+
+// 判断节点是否指向站内文档
+func (n *MenuNode) IsDocument() bool {
+	return n.Path != "" && !strings.Contains(n.Path, ":")
+}
+
+// 节点的链接地址
+func (n *MenuNode) Url() string {
+	if n.IsDocument() {
+		return "/" + n.Path
+	}
+	return n.Path
+}
+
+// 获得从顶级节点到当前节点的节点路径(面包屑)
+func (n *MenuNode) Breadcrumb() []*MenuNode {
+	nodes := make([]*MenuNode, n.Depth+1)
+	for node := n; node != nil; node = node.Parent {
+		nodes[node.Depth] = node
+	}
+	return nodes
+}
+
+// 自定义JSON编码，上级节点只输出其路径及标题，避免循环引用
+func (n *MenuNode) MarshalJSON() ([]byte, error) {
+	type menuParent struct {
+		Title string `json:"title"`
+		Path  string `json:"path"`
+	}
+	var parent *menuParent
+	if n.Parent != nil {
+		parent = &menuParent{n.Parent.Title, n.Parent.Path}
+	}
+	return json.Marshal(struct {
+		Title    string      `json:"title"`
+		Path     string      `json:"path"`
+		Depth    int         `json:"depth"`
+		Children []*MenuNode `json:"children"`
+		Parent   *menuParent `json:"parent"`
+	}{n.Title, n.Path, n.Depth, n.Children, parent})
+}
+
+// 文档翻页链接
+type PageLink struct {
+	Title string `json:"title"` // 文档标题
+	Path  string `json:"path"`  // 文档uri路径(不包含前导"/")
+}
+
+// 根据菜单中的文档顺序获得指定文档的上一篇及下一篇，不存在时返回nil
+func GetPrevNext(path string) (prev *PageLink, next *PageLink) {
+	menu := GetMenu(path)
+	node := menu.Find(path)
+	if node == nil {
+		return nil, nil
+	}
+	pages := menu.Pages()
+	for i, page := range pages {
+		if page != node {
+			continue
+		}
+		if i > 0 {
+			prev = &PageLink{pages[i-1].Title, pages[i-1].Path}
+		}
+		if i < len(pages)-1 {
+			next = &PageLink{pages[i+1].Title, pages[i+1].Path}
+		}
+		break
+	}
+	return
+}
+
+// 获得指定文档路径的面包屑节点列表，文档不在菜单中时返回空列表
+func GetBreadcrumb(path string) []*MenuNode {
+	if node := GetMenu(path).Find(path); node != nil {
+		return node.Breadcrumb()
+	}
+	return make([]*MenuNode, 0)
+}
+
+// 将菜单树渲染为嵌套列表的HTML，当前文档及其上级节点添加active样式
+func RenderMenu(nodes []*MenuNode, active string) string {
+	if len(nodes) == 0 {
+		return ""
+	}
+	actives := make(map[*MenuNode]bool)
+	if node := GetMenu(active).Find(active); node != nil {
+		for _, v := range node.Breadcrumb() {
+			actives[v] = true
+		}
+	}
+	buffer := bytes.NewBuffer(nil)
+	renderMenuNodes(buffer, nodes, actives)
+	return buffer.String()
+}
+
+// 递归渲染菜单节点
+func renderMenuNodes(buffer *bytes.Buffer, nodes []*MenuNode, actives map[*MenuNode]bool) {
+	buffer.WriteString(`<ul class="menu">`)
+	for _, node := range nodes {
+		if actives[node] {
+			buffer.WriteString(`<li class="active">`)
+		} else {
+			buffer.WriteString(`<li>`)
+		}
+		if node.Path != "" {
+			buffer.WriteString(`<a href="` + ghtml.SpecialChars(node.Url()) + `">` + ghtml.SpecialChars(node.Title) + `</a>`)
+		} else {
+			buffer.WriteString(`<span>` + ghtml.SpecialChars(node.Title) + `</span>`)
+		}
+		if len(node.Children) > 0 {
+			renderMenuNodes(buffer, node.Children, actives)
+		}
+		buffer.WriteString(`</li>`)
+	}
+	buffer.WriteString(`</ul>`)
+}
@@ -0,0 +1,72 @@
+package lib_document
+
+import (
+	"strings"
+	"testing"
+)
+
+// 将菜单树转换为便于比较的字符串，例如: 标题[路径](下级节点)
+func menuString(nodes []*MenuNode) string {
+	parts := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		s := node.Title + "[" + node.Path + "]"
+		if len(node.Children) > 0 {
+			s += "(" + menuString(node.Children) + ")"
+		}
+		parts = append(parts, s)
+	}
+	return strings.Join(parts, ",")
+}
+
+func TestParseMenu(t *testing.T) {
+	cases := []struct {
+		content string
+		want    string
+		pages   int
+	}{
+		{"", "", 0},
+		{"# Menu\n\nsome text\n", "", 0},
+		{"* [Home](index.md)\n* [About](./about.md#team)", "Home[index],About[about]", 2},
+		{
+			"* 核心模块\n    * [WebServer](net/ghttp/index.md)\n        * [路由](/net/ghttp/router.md)\n    * [数据库](database/gdb.md)\n* [首页](index.md)\n",
+			"核心模块[](WebServer[net/ghttp/index](路由[net/ghttp/router]),数据库[database/gdb]),首页[index]",
+			4,
+		},
+		{"- [A](a.md)\n\t+ [B](b.md)\n", "A[a](B[b])", 2},
+		{"* [GitHub](https://github.com/gogf/gf)\n* [Mail](mailto:a@b.c)\n", "GitHub[https://github.com/gogf/gf],Mail[mailto:a@b.c]", 0},
+		{"* [A](a.md)\n* [A again](a.md)\n", "A[a],A again[a]", 1},
+	}
+	for _, c := range cases {
+		menu := ParseMenu(c.content)
+		if got := menuString(menu.Nodes); got != c.want {
+			t.Errorf("ParseMenu(%q) = %s, want %s", c.content, got, c.want)
+		}
+		if len(menu.Pages()) != c.pages {
+			t.Errorf("ParseMenu(%q) pages = %d, want %d", c.content, len(menu.Pages()), c.pages)
+		}
+	}
+}
+
+func TestMenuNode(t *testing.T) {
+	menu := ParseMenu("* 模块\n    * [WebServer](net/ghttp.md)\n        * [路由](net/router.md)\n* [外链](https://goframe.org)\n")
+	node := menu.Find("net/router")
+	if node == nil {
+		t.Fatal("Find(net/router) = nil")
+	}
+	if node.Depth != 2 || node.Url() != "/net/router" {
+		t.Errorf("Depth = %d, Url = %s", node.Depth, node.Url())
+	}
+	titles := make([]string, 0)
+	for _, n := range node.Breadcrumb() {
+		titles = append(titles, n.Title)
+	}
+	if got := strings.Join(titles, "/"); got != "模块/WebServer/路由" {
+		t.Errorf("Breadcrumb = %s", got)
+	}
+	if link := menu.Nodes[1]; link.IsDocument() || link.Url() != "https://goframe.org" {
+		t.Errorf("external link IsDocument = %v, Url = %s", link.IsDocument(), link.Url())
+	}
+	if menu.Find("missing") != nil {
+		t.Errorf("Find(missing) should be nil")
+	}
+}
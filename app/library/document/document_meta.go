@@ -0,0 +1,148 @@
+package lib_document
+
+import (
+	"github.com/gogf/gf/g"
+	"github.com/gogf/gf/g/encoding/gtoml"
+	"github.com/gogf/gf/g/encoding/gyaml"
+	"github.com/gogf/gf/g/os/gfcache"
+	"github.com/gogf/gf/g/os/glog"
+	"github.com/gogf/gf/g/os/gtime"
+	"github.com/gogf/gf/g/util/gconv"
+	"strings"
+	"time"
+)
+
+// 文档元数据，来源于markdown文件头部的front matter(YAML使用---分隔，TOML使用+++分隔)
+type Meta struct {
+	Title       string    `json:"title"`
+	Date        time.Time `json:"date"`
+	Author      string    `json:"author"`
+	Tags        []string  `json:"tags"`
+	Categories  []string  `json:"categories"` // 层级分类使用"/"分隔，例如: 核心模块/WebServer
+	Draft       bool      `json:"draft"`
+	Weight      int       `json:"weight"`
+	Aliases     []string  `json:"aliases"`
+	Description string    `json:"description"`
+}
+
+// 获得指定uri路径的markdown文档元数据及去掉front matter后的正文内容
+func GetMarkdownWithMeta(path string) (*Meta, string) {
+	return ParseFrontMatter(GetMarkdown(path))
+}
+
+// 获得指定uri路径的markdown文档元数据
+func GetMeta(path string) *Meta {
+	meta, _ := GetMarkdownWithMeta(path)
+	return meta
+}
+
+// 解析markdown内容头部的front matter，返回元数据及正文内容，
+// 不包含front matter时返回空的元数据及原始内容
+func ParseFrontMatter(content string) (*Meta, string) {
+	meta := &Meta{}
+	text := strings.TrimPrefix(content, "\uFEFF")
+	if len(text) < 3 || (text[:3] != "---" && text[:3] != "+++") {
+		return meta, content
+	}
+	delimiter := text[:3]
+	lines     := strings.SplitAfter(text, "\n")
+	if strings.TrimSpace(lines[0]) != delimiter {
+		return meta, content
+	}
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) != delimiter {
+			continue
+		}
+		block := strings.Join(lines[1:i], "")
+		body  := strings.Join(lines[i+1:], "")
+		var (
+			data interface{}
+			err  error
+		)
+		if delimiter == "---" {
+			data, err = gyaml.Decode([]byte(block))
+		} else {
+			data, err = gtoml.Decode([]byte(block))
+		}
+		if err != nil {
+			glog.Cat("document").Printfln("front matter parse error: %v", err)
+			return meta, body
+		}
+		fillMeta(meta, gconv.Map(data))
+		return meta, body
+	}
+	return meta, content
+}
+
+// 将front matter数据填充到元数据对象，键名不区分大小写
+func fillMeta(meta *Meta, data map[string]interface{}) {
+	for k, v := range data {
+		switch strings.ToLower(k) {
+		case "title":
+			meta.Title = gconv.String(v)
+		case "date":
+			meta.Date = parseMetaTime(v)
+		case "author":
+			meta.Author = gconv.String(v)
+		case "tags":
+			meta.Tags = metaStrings(v)
+		case "categories", "category":
+			meta.Categories = append(meta.Categories, metaStrings(v)...)
+		case "draft":
+			meta.Draft = gconv.Bool(v)
+		case "weight":
+			meta.Weight = gconv.Int(v)
+		case "aliases":
+			meta.Aliases = metaStrings(v)
+		case "description":
+			meta.Description = gconv.String(v)
+		}
+	}
+}
+
+// 解析元数据中的时间，支持YAML/TOML的原生时间类型及常见的日期字符串格式
+func parseMetaTime(v interface{}) time.Time {
+	if t, ok := v.(time.Time); ok {
+		return t
+	}
+	if t, err := gtime.StrToTime(gconv.String(v)); err == nil {
+		return t.Time
+	}
+	return time.Time{}
+}
+
+// 元数据中的字符串列表，兼容使用逗号分隔的字符串
+func metaStrings(v interface{}) []string {
+	if s, ok := v.(string); ok {
+		v = strings.Split(s, ",")
+	}
+	result := make([]string, 0)
+	for _, item := range gconv.Strings(v) {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// 根据文档元数据中的aliases获得别名对应的文档uri路径，不存在时返回空字符串
+func GetPathByAlias(alias string) string {
+	version, alias := getVersionByUri(alias)
+	v := version.cache.GetOrSetFunc("doc_aliases", func() interface{} {
+		aliases := make(map[string]string)
+		for _, file := range version.getMdFiles() {
+			meta, _ := ParseFrontMatter(gfcache.GetContents(file))
+			for _, item := range meta.Aliases {
+				aliases[strings.Trim(item, "/")] = strings.TrimLeft(getUriByFilePath(file), "/")
+			}
+		}
+		return aliases
+	}, 0)
+	return v.(map[string]string)[strings.Trim(alias, "/")]
+}
+
+// 判断markdown内容是否为需要隐藏的草稿文档(草稿文档只在开发模式下可见)
+func isHiddenDraft(content string) bool {
+	meta, _ := ParseFrontMatter(content)
+	return meta.Draft && !g.Config().GetBool("setting.devmode")
+}
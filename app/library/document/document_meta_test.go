@@ -0,0 +1,49 @@
+package lib_document
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseFrontMatter(t *testing.T) {
+	date  := time.Date(2019, 6, 1, 0, 0, 0, 0, time.UTC)
+	cases := []struct {
+		name    string
+		content string
+		meta    Meta
+		body    string
+	}{
+		{"none", "# Title\n", Meta{}, "# Title\n"},
+		{"empty", "", Meta{}, ""},
+		{
+			"yaml",
+			"---\ntitle: Hello\nauthor: john\ntags: [go, web]\ncategories: 核心模块/WebServer\ndraft: true\nweight: 3\n---\n# Body\n",
+			Meta{Title: "Hello", Author: "john", Tags: []string{"go", "web"}, Categories: []string{"核心模块/WebServer"}, Draft: true, Weight: 3},
+			"# Body\n",
+		},
+		{
+			"toml",
+			"+++\ntitle = \"Hello\"\ndate = 2019-06-01T00:00:00Z\naliases = [\"/old\"]\n+++\nbody",
+			Meta{Title: "Hello", Date: date, Aliases: []string{"/old"}},
+			"body",
+		},
+		{"bom", "\uFEFF---\ntitle: Bom\n---\nbody", Meta{Title: "Bom"}, "body"},
+		{"comma tags", "---\ntags: go, web ,\n---\n", Meta{Tags: []string{"go", "web"}}, ""},
+		{"case insensitive", "---\nTitle: Upper\nCategory: a\n---\n", Meta{Title: "Upper", Categories: []string{"a"}}, ""},
+		{"unterminated", "---\ntitle: x\nbody", Meta{}, "---\ntitle: x\nbody"},
+		{"not at start", "text\n---\ntitle: x\n---\n", Meta{}, "text\n---\ntitle: x\n---\n"},
+		{"delimiter with text", "--- x\ntitle: x\n---\n", Meta{}, "--- x\ntitle: x\n---\n"},
+		{"invalid yaml", "---\ntitle: [x\n---\nbody", Meta{}, "body"},
+	}
+	for _, c := range cases {
+		meta, body := ParseFrontMatter(c.content)
+		if !meta.Date.Equal(c.meta.Date) {
+			t.Errorf("%s: date = %v, want %v", c.name, meta.Date, c.meta.Date)
+		}
+		meta.Date, c.meta.Date = time.Time{}, time.Time{}
+		if !reflect.DeepEqual(*meta, c.meta) || body != c.body {
+			t.Errorf("%s: ParseFrontMatter = %+v, %q, want %+v, %q", c.name, *meta, body, c.meta, c.body)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package lib_document
+
+import (
+	"bytes"
+	"github.com/gogf/gf/g/encoding/ghtml"
+	"github.com/gogf/gf/g/text/gregex"
+	"github.com/russross/blackfriday"
+	"io"
+	"strings"
+)
+
+// 用户输入(评论等)使用的markdown渲染器，只支持安全的语法子集：
+// 段落、强调、删除线、行内代码、代码块、列表、引用及http/https/mailto链接，
+// 标题渲染为加粗段落，原始html转义为文本，图片忽略
+type safeRenderer struct {
+	*blackfriday.HTMLRenderer
+}
+
+// 将用户输入的markdown内容解析为安全的html
+func ParseSafeMarkdown(content string) string {
+	renderer := &safeRenderer{
+		HTMLRenderer: blackfriday.NewHTMLRenderer(blackfriday.HTMLRendererParameters{
+			Flags: blackfriday.SkipImages | blackfriday.Safelink | blackfriday.NofollowLinks |
+				blackfriday.NoreferrerLinks | blackfriday.HrefTargetBlank,
+		}),
+	}
+	parser := blackfriday.New(
+		blackfriday.WithRenderer(renderer),
+		blackfriday.WithExtensions(blackfriday.NoIntraEmphasis | blackfriday.FencedCode | blackfriday.Autolink |
+			blackfriday.Strikethrough | blackfriday.HardLineBreak),
+	)
+	ast    := parser.Parse([]byte(content))
+	buffer := bytes.NewBuffer(nil)
+	ast.Walk(func(node *blackfriday.Node, entering bool) blackfriday.WalkStatus {
+		return renderer.RenderNode(buffer, node, entering)
+	})
+	return buffer.String()
+}
+
+// 对不允许的节点进行降级处理，其他节点交由blackfriday默认渲染
+func (r *safeRenderer) RenderNode(w io.Writer, node *blackfriday.Node, entering bool) blackfriday.WalkStatus {
+	switch node.Type {
+	case blackfriday.Heading:
+		if entering {
+			io.WriteString(w, "<p><strong>")
+		} else {
+			io.WriteString(w, "</strong></p>\n")
+		}
+		return blackfriday.GoToNext
+	case blackfriday.HTMLBlock, blackfriday.HTMLSpan:
+		renderEscapedHtml(w, node)
+		return blackfriday.GoToNext
+	case blackfriday.HorizontalRule:
+		return blackfriday.GoToNext
+	}
+	return r.HTMLRenderer.RenderNode(w, node, entering)
+}
+
+// 作者发布的文章使用的markdown渲染器，支持完整的markdown语法及代码高亮，
+// 原始html转义为文本，链接及图片只允许安全的协议
+type postRenderer struct {
+	*highlightRenderer
+}
+
+// 将作者发布的文章markdown内容解析为安全的html
+func ParsePostMarkdown(content string) string {
+	renderer := &postRenderer{highlightRenderer: newHighlightRenderer()}
+	renderer.Flags |= blackfriday.Safelink | blackfriday.NofollowLinks | blackfriday.NoreferrerLinks
+	parser   := blackfriday.New(
+		blackfriday.WithRenderer(renderer),
+		blackfriday.WithExtensions(blackfriday.CommonExtensions),
+	)
+	ast    := parser.Parse([]byte(normalizeFenceInfo(content)))
+	buffer := bytes.NewBuffer(nil)
+	ast.Walk(func(node *blackfriday.Node, entering bool) blackfriday.WalkStatus {
+		return renderer.RenderNode(buffer, node, entering)
+	})
+	return buffer.String()
+}
+
+// 转义原始html节点，忽略不安全协议的图片，其他节点交由代码高亮渲染器处理
+func (r *postRenderer) RenderNode(w io.Writer, node *blackfriday.Node, entering bool) blackfriday.WalkStatus {
+	switch node.Type {
+	case blackfriday.HTMLBlock, blackfriday.HTMLSpan:
+		renderEscapedHtml(w, node)
+		return blackfriday.GoToNext
+	case blackfriday.Image:
+		if entering && !isSafeImage(string(node.LinkData.Destination)) {
+			return blackfriday.SkipChildren
+		}
+	}
+	return r.highlightRenderer.RenderNode(w, node, entering)
+}
+
+// 将原始html节点作为文本输出，html块包装为段落
+func renderEscapedHtml(w io.Writer, node *blackfriday.Node) {
+	if node.Type == blackfriday.HTMLBlock {
+		io.WriteString(w, "<p>"+ghtml.SpecialChars(string(node.Literal))+"</p>\n")
+	} else {
+		io.WriteString(w, ghtml.SpecialChars(string(node.Literal)))
+	}
+}
+
+// 判断图片地址是否安全：相对地址或者http/https地址
+func isSafeImage(dest string) bool {
+	if !gregex.IsMatchString(`^[a-zA-Z][a-zA-Z0-9+.-]*:`, dest) {
+		return true
+	}
+	dest = strings.ToLower(dest)
+	return strings.HasPrefix(dest, "http://") || strings.HasPrefix(dest, "https://")
+}
@@ -0,0 +1,33 @@
+package lib_document
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParsePostMarkdown(t *testing.T) {
+	cases := []struct {
+		name    string
+		content string
+		want    string
+		deny    string
+	}{
+		{"heading", "# Title", "<h1>Title</h1>", ""},
+		{"html block", "<script>alert(1)</script>", "&lt;script&gt;alert(1)&lt;/script&gt;", "<script>"},
+		{"html span", "a <img src=x onerror=alert(1)> b", "&lt;img src=x onerror=alert(1)&gt;", "<img"},
+		{"javascript link", "[x](javascript:alert(1))", "<tt>x</tt>", "javascript:"},
+		{"javascript image", "![x](javascript:alert(1))", "", "javascript:"},
+		{"http image", "![x](https://goframe.org/a.png)", `<img src="https://goframe.org/a.png" alt="x" />`, ""},
+		{"relative image", "![x](images/a.png)", `<img src="images/a.png" alt="x" />`, ""},
+		{"highlight", "```go\nfunc main() {}\n```", `class="highlight`, ""},
+	}
+	for _, c := range cases {
+		got := ParsePostMarkdown(c.content)
+		if !strings.Contains(got, c.want) {
+			t.Errorf("%s: ParsePostMarkdown = %q, want containing %q", c.name, got, c.want)
+		}
+		if c.deny != "" && strings.Contains(got, c.deny) {
+			t.Errorf("%s: ParsePostMarkdown = %q, must not contain %q", c.name, got, c.deny)
+		}
+	}
+}
@@ -0,0 +1,249 @@
+package lib_document
+
+import (
+	"gf-blog/app/library/search"
+	"gf-blog/app/library/tokenizer"
+	"github.com/gogf/gf/g"
+	"github.com/gogf/gf/g/encoding/ghtml"
+	"github.com/gogf/gf/g/os/gfile"
+	"github.com/gogf/gf/g/os/glog"
+	"github.com/gogf/gf/g/text/gregex"
+	"sort"
+	"strings"
+)
+
+const (
+	// 检索结果片段的最大字符数
+	snippetWidth = 120
+	// 每个检索结果的最大片段数
+	snippetLimit = 3
+)
+
+// 文档检索结果项
+type SearchHit struct {
+	Path     string          `json:"path"`     // 文档路径
+	Title    string          `json:"title"`    // 文档层级标题
+	Section  string          `json:"section"`  // 匹配度最高的片段所在章节标题
+	Score    float64         `json:"score"`    // 相关度得分
+	Snippets []SearchSnippet `json:"snippets"` // 匹配上下文片段
+}
+
+// 检索结果上下文片段
+type SearchSnippet struct {
+	Section    string             `json:"section"`    // 片段所在章节标题
+	Text       string             `json:"text"`       // 片段纯文本内容
+	Highlights []lib_search.Range `json:"highlights"` // 匹配位置列表(字符偏移量，左闭右开)
+	Html       string             `json:"html"`       // 使用<mark>标记匹配内容的HTML
+}
+
+// 根据关键字进行当前版本的markdown文档搜索，返回文档path列表
+func SearchMdByKey(key string) []string {
+	results := SearchDocument("", key)
+	paths   := make([]string, len(results))
+	for i, result := range results {
+		paths[i] = result.Path
+	}
+	return paths
+}
+
+// 根据查询语句在指定版本(为空表示当前版本)中进行markdown文档全文检索，返回按照相关度排序的文档path及得分列表
+func SearchDocument(version string, query string) []lib_search.Result {
+	glog.Cat("search").Println(query)
+	return getVersionByName(version).getSearchIndex().Search(query)
+}
+
+// 重建所有版本的文档全文检索索引
+func BuildSearchIndex() {
+	for _, v := range GetVersions() {
+		v.indexMu.Lock()
+		v.buildSearchIndex()
+		v.indexMu.Unlock()
+	}
+}
+
+// 获得检索使用的分词词典，配置了search.dict时加载词典文件并与内置词典合并
+func getDictionary() *lib_tokenizer.Dictionary {
+	v := cache.GetOrSetFunc("search_dictionary", func() interface{} {
+		if path := g.Config().GetString("search.dict"); path != "" {
+			if gfile.Exists(path) {
+				return lib_tokenizer.LoadDictionary(path)
+			}
+			glog.Cat("search").Printfln("search dictionary not found: %s", path)
+		}
+		return lib_tokenizer.DefaultDictionary()
+	}, 0)
+	return v.(*lib_tokenizer.Dictionary)
+}
+
+// 获得检索使用的分词器
+func getTokenizer() lib_tokenizer.Tokenizer {
+	v := cache.GetOrSetFunc("search_tokenizer", func() interface{} {
+		return lib_tokenizer.New(getDictionary())
+	}, 0)
+	return v.(lib_tokenizer.Tokenizer)
+}
+
+// 获得导出检索索引使用的分词器，客户端检索不执行词干提取，导出的英文词项需要保持原词形
+func getExportTokenizer() lib_tokenizer.Tokenizer {
+	tokenizer := lib_tokenizer.New(getDictionary())
+	tokenizer.SetStemEnabled(false)
+	return tokenizer
+}
+
+// 导出的检索索引，用于静态站点的客户端检索
+type SearchExport struct {
+	*lib_search.IndexData
+	Titles []string `json:"titles"` // 与文档ID列表对应的文档层级标题
+	Texts  []string `json:"texts"`  // 与文档ID列表对应的文档纯文本内容
+}
+
+// 导出指定版本(为空表示当前版本)的检索索引，使用不提取词干的分词器重新构建，与客户端的查询词保持一致
+func ExportSearchIndex(version string) *SearchExport {
+	data   := getVersionByName(version).newSearchIndex(getExportTokenizer()).Export()
+	export := &SearchExport{
+		IndexData: data,
+		Titles:    make([]string, len(data.Ids)),
+		Texts:     make([]string, len(data.Ids)),
+	}
+	for i, path := range data.Ids {
+		export.Titles[i] = GetTitleByPath(path)
+		_, content := GetMarkdownWithMeta(path)
+		lines      := make([]string, 0)
+		for _, line := range strings.Split(content, "\n") {
+			if line = cleanMarkdownLine(strings.TrimSpace(line)); line != "" && !strings.HasPrefix(line, "```") {
+				lines = append(lines, line)
+			}
+		}
+		export.Texts[i] = strings.Join(lines, "\n")
+	}
+	return export
+}
+
+// 根据指定版本的检索结果生成包含标题、章节及上下文片段的检索结果项列表
+func GetSearchHits(version string, query string, results []lib_search.Result) []SearchHit {
+	index := getVersionByName(version).getSearchIndex()
+	terms := index.QueryTerms(query)
+	hits  := make([]SearchHit, len(results))
+	for i, result := range results {
+		hits[i] = SearchHit{
+			Path:     result.Path,
+			Title:    GetTitleByPath(result.Path),
+			Score:    result.Score,
+			Snippets: getSnippets(index, result.Path, terms),
+		}
+		if len(hits[i].Snippets) > 0 {
+			hits[i].Section = hits[i].Snippets[0].Section
+		}
+	}
+	return hits
+}
+
+// 获得文档中与查询词项匹配的上下文片段，按照匹配数量从高到低排序
+func getSnippets(index *lib_search.Index, path string, terms []string) []SearchSnippet {
+	snippets := make([]SearchSnippet, 0)
+	counts   := make([]int, 0)
+	section  := ""
+	_, content := GetMarkdownWithMeta(path)
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "```") {
+			continue
+		}
+		text := cleanMarkdownLine(line)
+		if match, _ := gregex.MatchString(`^#{1,6}\s+`, line); len(match) > 0 {
+			section = text
+		}
+		ranges := index.Highlight(text, terms)
+		if len(ranges) == 0 {
+			continue
+		}
+		snippets = append(snippets, cropSnippet(section, text, ranges))
+		counts   = append(counts, len(ranges))
+	}
+	order := make([]int, len(snippets))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return counts[order[i]] > counts[order[j]]
+	})
+	if len(order) > snippetLimit {
+		order = order[:snippetLimit]
+	}
+	result := make([]SearchSnippet, len(order))
+	for i, n := range order {
+		result[i] = snippets[n]
+	}
+	return result
+}
+
+// 将markdown行转换为纯文本，去掉标题、列表、引用标记以及链接、图片、强调等行内语法
+func cleanMarkdownLine(line string) string {
+	line, _ = gregex.ReplaceString(`^(#{1,6}|[*+\-]|\d+\.|>)\s+`, "", line)
+	line, _ = gregex.ReplaceString(`!?\[([^\]]*)\]\([^)]*\)`, "$1", line)
+	line, _ = gregex.ReplaceString("(\\*\\*|__|`)", "", line)
+	return strings.TrimSpace(line)
+}
+
+// 根据第一个匹配位置截取片段，并生成高亮HTML
+func cropSnippet(section string, text string, ranges []lib_search.Range) SearchSnippet {
+	runes := []rune(text)
+	start := 0
+	end   := len(runes)
+	if end > snippetWidth {
+		start = ranges[0][0] - snippetWidth/4
+		if start < 0 {
+			start = 0
+		}
+		if start+snippetWidth < end {
+			end = start + snippetWidth
+		} else {
+			start = end - snippetWidth
+		}
+	}
+	prefix := ""
+	suffix := ""
+	if start > 0 {
+		prefix = "…"
+	}
+	if end < len(runes) {
+		suffix = "…"
+	}
+	offset     := len([]rune(prefix)) - start
+	highlights := make([]lib_search.Range, 0, len(ranges))
+	for _, r := range ranges {
+		if r[1] <= start || r[0] >= end {
+			continue
+		}
+		if r[0] < start {
+			r[0] = start
+		}
+		if r[1] > end {
+			r[1] = end
+		}
+		highlights = append(highlights, lib_search.Range{r[0] + offset, r[1] + offset})
+	}
+	snippet := SearchSnippet{
+		Section:    section,
+		Text:       prefix + string(runes[start:end]) + suffix,
+		Highlights: highlights,
+	}
+	snippet.Html = highlightHtml(snippet.Text, snippet.Highlights)
+	return snippet
+}
+
+// 将文本中的匹配区间使用<mark>标记，其余内容执行HTML转义
+func highlightHtml(text string, ranges []lib_search.Range) string {
+	runes  := []rune(text)
+	buffer := strings.Builder{}
+	last   := 0
+	for _, r := range ranges {
+		buffer.WriteString(ghtml.SpecialChars(string(runes[last:r[0]])))
+		buffer.WriteString("<mark>")
+		buffer.WriteString(ghtml.SpecialChars(string(runes[r[0]:r[1]])))
+		buffer.WriteString("</mark>")
+		last = r[1]
+	}
+	buffer.WriteString(ghtml.SpecialChars(string(runes[last:])))
+	return buffer.String()
+}
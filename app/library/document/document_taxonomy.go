@@ -0,0 +1,52 @@
+package lib_document
+
+import (
+	"github.com/gogf/gf/g/os/gfcache"
+	"strings"
+	"time"
+)
+
+// 设置了标签或者分类的文档
+type TermDocument struct {
+	Path       string    // 文档uri路径(不包含前导"/")
+	Title      string    // 文档标题
+	Date       time.Time // 发布时间，front matter中未设置date时为更新时间
+	Tags       []string  // 标签列表
+	Categories []string  // 分类列表，层级分类使用"/"分隔
+}
+
+// 获得当前版本中设置了标签或者分类的文档列表，不包含菜单文档及草稿文档，只在文档变更时重新构建
+func GetTermDocuments() []*TermDocument {
+	v := getDefaultVersion()
+	return v.cache.GetOrSetFunc("doc_terms", func() interface{} {
+		docs := make([]*TermDocument, 0)
+		for _, file := range v.getMdFiles() {
+			path := strings.TrimLeft(getUriByFilePath(file), "/")
+			if path == GetMenuPath(path) {
+				continue
+			}
+			meta, _ := ParseFrontMatter(gfcache.GetContents(file))
+			if meta.Draft || (len(meta.Tags) == 0 && len(meta.Categories) == 0) {
+				continue
+			}
+			doc := &TermDocument{
+				Path:       path,
+				Title:      meta.Title,
+				Date:       meta.Date,
+				Tags:       meta.Tags,
+				Categories: meta.Categories,
+			}
+			if doc.Title == "" {
+				doc.Title = GetTitleByPath(path)
+			}
+			if doc.Title == "" {
+				doc.Title = path
+			}
+			if doc.Date.IsZero() {
+				doc.Date = GetUpdatedTime(path)
+			}
+			docs = append(docs, doc)
+		}
+		return docs
+	}, 0).([]*TermDocument)
+}
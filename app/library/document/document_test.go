@@ -0,0 +1,57 @@
+package lib_document
+
+import (
+	"fmt"
+	"github.com/gogf/gf/g"
+	"github.com/gogf/gf/g/os/gfile"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// 测试使用的文档目录，在TestMain中创建
+var testDocRoot string
+
+// 创建临时的配置文件及文档目录，所有测试共用
+func TestMain(m *testing.M) {
+	dir, err := ioutil.TempDir("", "gf-blog-document")
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	testDocRoot = filepath.Join(dir, "docs")
+	config     := fmt.Sprintf("[document]\n    path = %q\n[version]\n    path = %q\n",
+		filepath.ToSlash(testDocRoot), filepath.ToSlash(filepath.Join(dir, "versions")))
+	gfile.PutContents(filepath.Join(dir, "config.toml"), config)
+	gfile.PutContents(filepath.Join(testDocRoot, "index.md"), "# Home\n")
+	gfile.PutContents(filepath.Join(testDocRoot, "menus.md"), "* [Home](index.md)\n")
+	g.Config().SetPath(dir)
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+func TestIsDocumentPath(t *testing.T) {
+	gfile.PutContents(filepath.Join(testDocRoot, "draft.md"), "---\ndraft: true\n---\n# Draft\n")
+	gfile.PutContents(filepath.Join(filepath.Dir(testDocRoot), "outside.md"), "# Outside\n")
+	cases := []struct {
+		path string
+		want bool
+	}{
+		{"/index", true},
+		{"index/", true},
+		{"", false},
+		{"/", false},
+		{"/menus", false},
+		{"/draft", false},
+		{"/missing", false},
+		{"/../outside", false},
+		{"/a/../../outside", false},
+	}
+	for _, c := range cases {
+		if got := IsDocumentPath(c.path); got != c.want {
+			t.Errorf("IsDocumentPath(%q) = %v, want %v", c.path, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package lib_document
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/gogf/gf/g/encoding/ghtml"
+	"github.com/russross/blackfriday"
+	"strings"
+	"unicode"
+)
+
+// 文档目录项
+type TocItem struct {
+	Id       string     `json:"id"`       // 标题锚点ID
+	Title    string     `json:"title"`    // 标题文本
+	Level    int        `json:"level"`    // 标题级别(1-6)
+	Children []*TocItem `json:"children"` // 下级目录项
+}
+
+// 使用blackfriday渲染markdown(代码块进行语法高亮)，为所有标题生成稳定且唯一的锚点ID，并返回目录树
+func renderMarkdown(content string) (string, []*TocItem) {
+	renderer := newHighlightRenderer()
+	parser   := blackfriday.New(
+		blackfriday.WithRenderer(renderer),
+		blackfriday.WithExtensions(blackfriday.CommonExtensions),
+	)
+	ast   := parser.Parse([]byte(normalizeFenceInfo(content)))
+	ids   := make(map[string]int)
+	items := make([]*TocItem, 0)
+	ast.Walk(func(node *blackfriday.Node, entering bool) blackfriday.WalkStatus {
+		if !entering || node.Type != blackfriday.Heading || node.IsTitleblock {
+			return blackfriday.GoToNext
+		}
+		title := headingText(node)
+		id    := node.HeadingID
+		if id == "" {
+			id = anchorId(title)
+		}
+		node.HeadingID = uniqueAnchorId(id, ids)
+		items = append(items, &TocItem{
+			Id:       node.HeadingID,
+			Title:    title,
+			Level:    node.Level,
+			Children: make([]*TocItem, 0),
+		})
+		return blackfriday.SkipChildren
+	})
+	buffer := bytes.NewBuffer(nil)
+	renderer.RenderHeader(buffer, ast)
+	ast.Walk(func(node *blackfriday.Node, entering bool) blackfriday.WalkStatus {
+		return renderer.RenderNode(buffer, node, entering)
+	})
+	renderer.RenderFooter(buffer, ast)
+	return buffer.String(), buildTocTree(items)
+}
+
+// 获得标题节点的纯文本内容
+func headingText(node *blackfriday.Node) string {
+	buffer := bytes.NewBuffer(nil)
+	node.Walk(func(child *blackfriday.Node, entering bool) blackfriday.WalkStatus {
+		if entering && (child.Type == blackfriday.Text || child.Type == blackfriday.Code) {
+			buffer.Write(child.Literal)
+		}
+		return blackfriday.GoToNext
+	})
+	return strings.TrimSpace(buffer.String())
+}
+
+// 根据标题文本生成锚点ID：保留各语言的字母及数字(包括中文)，英文转换为小写，其他字符替换为"-"
+func anchorId(title string) string {
+	buffer := bytes.NewBuffer(nil)
+	dash   := false
+	for _, r := range strings.ToLower(title) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+			if dash && buffer.Len() > 0 {
+				buffer.WriteByte('-')
+			}
+			buffer.WriteRune(r)
+			dash = false
+		} else {
+			dash = true
+		}
+	}
+	if buffer.Len() == 0 {
+		return "section"
+	}
+	return buffer.String()
+}
+
+// 保证锚点ID在文档内唯一，重复时按照出现顺序添加"-1"、"-2"等后缀
+func uniqueAnchorId(id string, ids map[string]int) string {
+	result := id
+	for {
+		n, ok := ids[result]
+		if !ok {
+			break
+		}
+		ids[result] = n + 1
+		result = fmt.Sprintf("%s-%d", id, n+1)
+	}
+	ids[result] = 0
+	return result
+}
+
+// 根据标题级别将目录项列表构建为目录树
+func buildTocTree(items []*TocItem) []*TocItem {
+	root  := make([]*TocItem, 0)
+	stack := make([]*TocItem, 0)
+	for _, item := range items {
+		for len(stack) > 0 && stack[len(stack)-1].Level >= item.Level {
+			stack = stack[:len(stack)-1]
+		}
+		if len(stack) == 0 {
+			root = append(root, item)
+		} else {
+			parent := stack[len(stack)-1]
+			parent.Children = append(parent.Children, item)
+		}
+		stack = append(stack, item)
+	}
+	return root
+}
+
+// 将目录树渲染为嵌套列表的HTML
+func RenderToc(items []*TocItem) string {
+	if len(items) == 0 {
+		return ""
+	}
+	buffer := bytes.NewBuffer(nil)
+	buffer.WriteString(`<ul class="toc">`)
+	for _, item := range items {
+		buffer.WriteString(fmt.Sprintf(
+			`<li><a href="#%s">%s</a>%s</li>`,
+			ghtml.SpecialChars(item.Id), ghtml.SpecialChars(item.Title), RenderToc(item.Children),
+		))
+	}
+	buffer.WriteString(`</ul>`)
+	return buffer.String()
+}
@@ -0,0 +1,92 @@
+package lib_document
+
+import (
+	"strings"
+	"testing"
+)
+
+// 将目录树转换为便于比较的字符串，例如: a(b,c)
+func tocString(items []*TocItem) string {
+	parts := make([]string, 0, len(items))
+	for _, item := range items {
+		s := item.Id
+		if len(item.Children) > 0 {
+			s += "(" + tocString(item.Children) + ")"
+		}
+		parts = append(parts, s)
+	}
+	return strings.Join(parts, ",")
+}
+
+func TestAnchorId(t *testing.T) {
+	cases := []struct {
+		title string
+		want  string
+	}{
+		{"Hello World", "hello-world"},
+		{"  Hello,  World!  ", "hello-world"},
+		{"路由注册 Router", "路由注册-router"},
+		{"go_lang 1.11", "go_lang-1-11"},
+		{"!!!", "section"},
+	}
+	for _, c := range cases {
+		if got := anchorId(c.title); got != c.want {
+			t.Errorf("anchorId(%q) = %q, want %q", c.title, got, c.want)
+		}
+	}
+}
+
+func TestParseMarkdownWithToc(t *testing.T) {
+	cases := []struct {
+		markdown string
+		toc      string
+		html     []string
+	}{
+		{"", "", nil},
+		{"text only", "", []string{"<p>text only</p>"}},
+		{
+			"# A\n## B\n### C\n## D\n# E\n",
+			"a(b(c),d),e",
+			[]string{`<h1 id="a">A</h1>`, `<h3 id="c">C</h3>`},
+		},
+		{
+			"## Intro\n## Intro\n## Intro\n",
+			"intro,intro-1,intro-2",
+			[]string{`<h2 id="intro-1">Intro</h2>`},
+		},
+		{
+			"### Deep\n# Top\n",
+			"deep,top",
+			nil,
+		},
+		{
+			"# Custom {#my-id}\n# `code` title\n",
+			"my-id,code-title",
+			[]string{`<h1 id="my-id">Custom</h1>`},
+		},
+	}
+	for _, c := range cases {
+		html, toc := ParseMarkdownWithToc(c.markdown)
+		if got := tocString(toc); got != c.toc {
+			t.Errorf("toc(%q) = %s, want %s", c.markdown, got, c.toc)
+		}
+		for _, want := range c.html {
+			if !strings.Contains(html, want) {
+				t.Errorf("html(%q) = %s, want containing %s", c.markdown, html, want)
+			}
+		}
+	}
+}
+
+func TestRenderToc(t *testing.T) {
+	_, toc := ParseMarkdownWithToc("# A & B\n## C\n")
+	html   := RenderToc(toc)
+	for _, want := range []string{`<ul class="toc">`, `href="#a-b"`, `A &amp; B`, `href="#c"`} {
+		if !strings.Contains(html, want) {
+			t.Errorf("RenderToc = %s, want containing %s", html, want)
+		}
+	}
+	if RenderToc(nil) != "" {
+		t.Errorf("RenderToc(nil) should be empty")
+	}
+}
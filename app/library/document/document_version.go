@@ -0,0 +1,291 @@
+package lib_document
+
+import (
+	"gf-blog/app/library/search"
+	"gf-blog/app/library/tokenizer"
+	"github.com/gogf/gf/g"
+	"github.com/gogf/gf/g/container/gtype"
+	"github.com/gogf/gf/g/os/gcache"
+	"github.com/gogf/gf/g/os/gfcache"
+	"github.com/gogf/gf/g/os/gfile"
+	"github.com/gogf/gf/g/os/glog"
+	"github.com/gogf/gf/g/util/gconv"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// 文档版本，除当前版本外，每个版本对应文档版本库的一个分支或者标签，使用git worktree检出到独立目录
+type Version struct {
+	Name    string `json:"name"`  // 版本名称，作为文档uri路径前缀(例如: v1.5)，当前版本为空
+	Title   string `json:"title"` // 版本显示名称
+	Ref     string `json:"ref"`   // git分支或者标签名称
+	root    string                // 文档目录绝对路径
+	cache   *gcache.Cache         // 版本独立的缓存命名空间
+	index   *gtype.Interface      // 版本独立的全文检索索引(*lib_search.Index)
+	indexMu sync.Mutex            // 检索索引构建互斥锁，防止并发重复构建
+}
+
+// 版本切换链接
+type VersionLink struct {
+	Name    string `json:"name"`    // 版本名称
+	Title   string `json:"title"`   // 版本显示名称
+	Path    string `json:"path"`    // 同一文档在该版本下的uri路径(不包含前导"/")
+	Current bool   `json:"current"` // 是否为当前访问的版本
+}
+
+var (
+	// 版本列表，第一项为当前版本(document.path)
+	versions     []*Version
+	versionsOnce sync.Once
+)
+
+// 获得所有文档版本，第一项为当前版本
+func GetVersions() []*Version {
+	versionsOnce.Do(func() {
+		c := g.Config()
+		title := c.GetString("version.current")
+		if title == "" {
+			title = GetDocBranch()
+		}
+		versions = []*Version{{
+			Title: title,
+			Ref:   GetDocBranch(),
+			root:  getDocRoot(),
+			cache: cache,
+			index: gtype.NewInterface(),
+		}}
+		for _, item := range c.GetArray("version.list") {
+			m    := gconv.Map(item)
+			name := strings.Trim(gconv.String(m["name"]), "/")
+			if name == "" || strings.Contains(name, "/") {
+				continue
+			}
+			v := &Version{
+				Name:  name,
+				Title: gconv.String(m["title"]),
+				Ref:   gconv.String(m["ref"]),
+				root:  getVersionsRoot() + gfile.Separator + name,
+				cache: gcache.New(),
+				index: gtype.NewInterface(),
+			}
+			if v.Title == "" {
+				v.Title = v.Name
+			}
+			if v.Ref == "" {
+				v.Ref = v.Name
+			}
+			versions = append(versions, v)
+		}
+	})
+	return versions
+}
+
+// 获得当前版本
+func getDefaultVersion() *Version {
+	return GetVersions()[0]
+}
+
+// 获得版本worktree的存放目录绝对路径。目录不存在时也需要返回绝对路径，
+// 否则git worktree add会在文档目录(命令的执行目录)下创建worktree
+func getVersionsRoot() string {
+	path := g.Config().GetString("version.path")
+	if path == "" {
+		path = "./docfile-versions"
+	}
+	if realPath := gfile.RealPath(path); realPath != "" {
+		return realPath
+	}
+	if absPath, err := filepath.Abs(path); err == nil {
+		return absPath
+	}
+	return path
+}
+
+// 根据文档uri路径获得所属版本以及版本内的相对路径，路径不包含版本前缀时属于当前版本
+func getVersionByUri(uri string) (*Version, string) {
+	uri = strings.Trim(uri, "/")
+	for _, v := range GetVersions()[1:] {
+		if uri == v.Name {
+			return v, "index"
+		}
+		if strings.HasPrefix(uri, v.Name+"/") {
+			return v, uri[len(v.Name)+1:]
+		}
+	}
+	return getDefaultVersion(), uri
+}
+
+// 根据markdown文件绝对路径获得所属版本
+func getVersionByFilePath(path string) *Version {
+	for _, v := range GetVersions()[1:] {
+		if strings.HasPrefix(path, v.root+gfile.Separator) {
+			return v
+		}
+	}
+	return getDefaultVersion()
+}
+
+// 根据版本名称获得版本，不存在时返回当前版本
+func getVersionByName(name string) *Version {
+	for _, v := range GetVersions() {
+		if v.Name == name {
+			return v
+		}
+	}
+	return getDefaultVersion()
+}
+
+// 获得同一文档在各个版本下的切换链接，只有当前版本时返回空列表
+func GetVersionLinks(path string) []VersionLink {
+	current, rest := getVersionByUri(path)
+	links := make([]VersionLink, 0)
+	if len(GetVersions()) < 2 {
+		return links
+	}
+	for _, v := range GetVersions() {
+		links = append(links, VersionLink{
+			Name:    v.Name,
+			Title:   v.Title,
+			Path:    v.prefix() + rest,
+			Current: v == current,
+		})
+	}
+	return links
+}
+
+// 判断推送的git引用(refs/heads/xxx或者refs/tags/xxx)是否为文档版本跟踪的分支或者标签
+func IsTrackedRef(ref string) bool {
+	for _, v := range GetVersions() {
+		if ref == "refs/heads/"+v.Ref || ref == "refs/tags/"+v.Ref {
+			return true
+		}
+	}
+	return false
+}
+
+// 版本的文档uri路径前缀
+func (v *Version) prefix() string {
+	if v.Name == "" {
+		return ""
+	}
+	return v.Name + "/"
+}
+
+// 获得版本文档目录下所有markdown文件的绝对路径列表
+func (v *Version) getMdFiles() []string {
+	paths := v.cache.GetOrSetFunc("doc_files_recursive", func() interface{} {
+		// 当目录列表不存在时，执行检索
+		paths, _ := gfile.ScanDir(v.root, "*.md", true)
+		return paths
+	}, 0)
+	return gconv.Strings(paths)
+}
+
+// 获得版本的全文检索索引，索引不存在时执行构建
+func (v *Version) getSearchIndex() *lib_search.Index {
+	if index := v.index.Val(); index != nil {
+		return index.(*lib_search.Index)
+	}
+	v.indexMu.Lock()
+	defer v.indexMu.Unlock()
+	if v.index.Val() == nil {
+		v.buildSearchIndex()
+	}
+	return v.index.Val().(*lib_search.Index)
+}
+
+// 构建版本的全文检索索引，调用端需要加锁
+func (v *Version) buildSearchIndex() {
+	index := v.newSearchIndex(getTokenizer())
+	v.index.Set(index)
+	glog.Cat("search").Printfln("search index built for version %q, %d documents", v.Title, index.Len())
+}
+
+// 使用指定分词器创建包含版本所有文档(不包含隐藏的草稿)的全文检索索引
+func (v *Version) newSearchIndex(tokenizer lib_tokenizer.Tokenizer) *lib_search.Index {
+	index := lib_search.New(tokenizer)
+	for _, path := range v.getMdFiles() {
+		content := gfcache.GetContents(path)
+		if isHiddenDraft(content) {
+			continue
+		}
+		index.Add(getUriByFilePath(path), content)
+	}
+	return index
+}
+
+// 清除版本的所有缓存数据并重建全文检索索引
+func (v *Version) reset() {
+	for _, path := range v.getMdFiles() {
+		removeFileCache(path)
+	}
+	v.cache.Clear()
+	v.indexMu.Lock()
+	v.buildSearchIndex()
+	v.indexMu.Unlock()
+}
+
+// 检出或者更新所有非当前版本的worktree，需要在文档版本库更新互斥锁内执行
+func setupVersions() {
+	list := GetVersions()[1:]
+	if len(list) == 0 {
+		return
+	}
+	root := getDocRoot()
+	if _, err := runGit(root, "fetch", "--tags", getDocRemote()); err != nil {
+		glog.Cat("doc-version").Printfln("fetch versions error: %v", err)
+	}
+	for _, v := range list {
+		commit, err := resolveRef(v.Ref)
+		if err != nil {
+			glog.Cat("doc-version").Printfln("version %s: %v", v.Name, err)
+			continue
+		}
+		if !gfile.Exists(v.root) {
+			if _, err := runGit(root, "worktree", "add", "--detach", v.root, commit); err != nil {
+				glog.Cat("doc-version").Printfln("version %s: %v", v.Name, err)
+			} else {
+				glog.Cat("doc-version").Printfln("version %s checked out %s at %s", v.Name, v.Ref, shortHash(commit))
+			}
+			continue
+		}
+		oldHead := getHead(v.root)
+		if oldHead == commit {
+			continue
+		}
+		if _, err := runGit(v.root, "checkout", "-q", "--detach", commit); err != nil {
+			glog.Cat("doc-version").Printfln("version %s: %v", v.Name, err)
+			continue
+		}
+		glog.Cat("doc-version").Printfln("version %s updated %s..%s", v.Name, shortHash(oldHead), shortHash(commit))
+		if files, err := getChangedFiles(v.root, oldHead, commit); err == nil {
+			RefreshFiles(files)
+		} else {
+			v.reset()
+		}
+	}
+}
+
+// 检出或者更新所有文档版本的worktree
+func SetupVersions() {
+	updateMu.Lock()
+	defer updateMu.Unlock()
+	setupVersions()
+}
+
+// 将分支或者标签名称解析为提交哈希，远程分支优先
+func resolveRef(ref string) (string, error) {
+	var err error
+	for _, name := range []string{
+		"refs/remotes/" + getDocRemote() + "/" + ref,
+		"refs/tags/" + ref,
+		ref,
+	} {
+		var output string
+		if output, err = runGit(getDocRoot(), "rev-parse", "--verify", "-q", name+"^{commit}"); err == nil {
+			return strings.TrimSpace(output), nil
+		}
+	}
+	return "", err
+}
@@ -0,0 +1,97 @@
+package lib_document
+
+import (
+	"github.com/gogf/gf/g"
+	"github.com/gogf/gf/g/os/gfile"
+	"github.com/gogf/gf/g/os/gfsnotify"
+	"github.com/gogf/gf/g/os/glog"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// 默认的文件变更去抖动间隔(毫秒)
+	defaultWatchDebounce = 500
+)
+
+var (
+	// 等待刷新的变更路径
+	watchPending = make(map[string]struct{})
+	// 去抖动定时器
+	watchTimer *time.Timer
+	// 变更路径及定时器互斥锁
+	watchMu = sync.Mutex{}
+)
+
+// 递归监控文档目录的文件变更，变更事件在去抖动间隔内合并后批量刷新缓存数据
+func Watch() error {
+	root := getDocRoot()
+	_, err := gfsnotify.Add(root, func(event *gfsnotify.Event) {
+		// 文件属性变更(包括编辑器的"假重命名")不影响文档内容
+		if event.IsChmod() {
+			return
+		}
+		addWatchPending(event.Path)
+	}, true)
+	if err != nil {
+		return err
+	}
+	glog.Cat("doc-watch").Printfln("watching document path: %s", root)
+	return nil
+}
+
+// 添加变更路径，并重置去抖动定时器
+func addWatchPending(path string) {
+	debounce := g.Config().GetInt("document.watchDebounce")
+	if debounce <= 0 {
+		debounce = defaultWatchDebounce
+	}
+	watchMu.Lock()
+	defer watchMu.Unlock()
+	watchPending[path] = struct{}{}
+	if watchTimer == nil {
+		watchTimer = time.AfterFunc(time.Duration(debounce)*time.Millisecond, flushWatchPending)
+	} else {
+		watchTimer.Reset(time.Duration(debounce) * time.Millisecond)
+	}
+}
+
+// 刷新所有等待中的变更路径相关的缓存数据
+func flushWatchPending() {
+	watchMu.Lock()
+	pending     := watchPending
+	watchPending = make(map[string]struct{})
+	watchMu.Unlock()
+
+	files := make([]string, 0, len(pending))
+	for path := range pending {
+		files = append(files, expandWatchPath(path)...)
+	}
+	if len(files) > 0 {
+		glog.Cat("doc-watch").Printfln("documents changed: %s", strings.Join(files, ", "))
+		RefreshFiles(files)
+	}
+}
+
+// 将变更路径展开为markdown文件列表，目录的创建、删除及重命名会影响其下所有的markdown文件
+func expandWatchPath(path string) []string {
+	if gfile.Ext(path) == ".md" {
+		return []string{path}
+	}
+	files  := make([]string, 0)
+	prefix := path + gfile.Separator
+	// 目录删除或者重命名，之前已存在于文档列表中的文件
+	for _, file := range getDefaultVersion().getMdFiles() {
+		if strings.HasPrefix(file, prefix) {
+			files = append(files, file)
+		}
+	}
+	// 目录创建或者重命名后的新目录
+	if gfile.IsDir(path) {
+		if list, err := gfile.ScanDir(path, "*.md", true); err == nil {
+			files = append(files, list...)
+		}
+	}
+	return files
+}
@@ -0,0 +1,194 @@
+package lib_feed
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+// 订阅源
+type Feed struct {
+	Title       string    // 订阅源标题
+	Link        string    // 站点地址(绝对URL)
+	FeedLink    string    // 订阅源自身的地址(绝对URL)
+	Description string    // 订阅源描述
+	Updated     time.Time // 最后更新时间，为空时使用条目中最新的时间
+	Items       []*Item   // 订阅条目，按照时间从新到旧排序
+}
+
+// 订阅条目
+type Item struct {
+	Id        string    // 条目唯一标识，为空时使用Link
+	Title     string    // 条目标题
+	Link      string    // 条目地址(绝对URL)
+	Author    string    // 作者
+	Summary   string    // HTML格式的摘要
+	Published time.Time // 发布时间
+	Updated   time.Time // 更新时间，为空时使用Published
+}
+
+// RSS 2.0 文档结构
+type rss struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Atom    string     `xml:"xmlns:atom,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	AtomLink      atomLink  `xml:"atom:link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	Guid        rssGuid `xml:"guid"`
+	Author      string  `xml:"author,omitempty"`
+	Description string  `xml:"description"`
+	PubDate     string  `xml:"pubDate,omitempty"`
+}
+
+type rssGuid struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// Atom 文档结构
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	Id      string      `xml:"id"`
+	Links   []atomLink  `xml:"link"`
+	Updated string      `xml:"updated"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomEntry struct {
+	Title     string      `xml:"title"`
+	Id        string      `xml:"id"`
+	Link      atomLink    `xml:"link"`
+	Author    *atomAuthor `xml:"author,omitempty"`
+	Published string      `xml:"published,omitempty"`
+	Updated   string      `xml:"updated"`
+	Summary   atomText    `xml:"summary"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomText struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+// 生成RSS 2.0格式的订阅内容
+func (f *Feed) Rss() ([]byte, error) {
+	doc := rss{
+		Version: "2.0",
+		Atom:    "http://www.w3.org/2005/Atom",
+		Channel: rssChannel{
+			Title:       f.Title,
+			Link:        f.Link,
+			AtomLink:    atomLink{Href: f.FeedLink, Rel: "self", Type: "application/rss+xml"},
+			Description: f.Description,
+			Items:       make([]rssItem, 0, len(f.Items)),
+		},
+	}
+	if updated := f.updated(); !updated.IsZero() {
+		doc.Channel.LastBuildDate = updated.Format(time.RFC1123Z)
+	}
+	for _, item := range f.Items {
+		v := rssItem{
+			Title:       item.Title,
+			Link:        item.Link,
+			Guid:        rssGuid{IsPermaLink: item.Id == "", Value: item.id()},
+			Author:      item.Author,
+			Description: item.Summary,
+		}
+		if !item.Published.IsZero() {
+			v.PubDate = item.Published.Format(time.RFC1123Z)
+		}
+		doc.Channel.Items = append(doc.Channel.Items, v)
+	}
+	return marshal(doc)
+}
+
+// 生成Atom格式的订阅内容
+func (f *Feed) Atom() ([]byte, error) {
+	doc := atomFeed{
+		Title: f.Title,
+		Id:    f.FeedLink,
+		Links: []atomLink{
+			{Href: f.Link, Rel: "alternate", Type: "text/html"},
+			{Href: f.FeedLink, Rel: "self", Type: "application/atom+xml"},
+		},
+		Updated: f.updated().Format(time.RFC3339),
+		Entries: make([]atomEntry, 0, len(f.Items)),
+	}
+	for _, item := range f.Items {
+		entry := atomEntry{
+			Title:   item.Title,
+			Id:      item.id(),
+			Link:    atomLink{Href: item.Link, Rel: "alternate", Type: "text/html"},
+			Updated: item.updated().Format(time.RFC3339),
+			Summary: atomText{Type: "html", Value: item.Summary},
+		}
+		if item.Author != "" {
+			entry.Author = &atomAuthor{Name: item.Author}
+		}
+		if !item.Published.IsZero() {
+			entry.Published = item.Published.Format(time.RFC3339)
+		}
+		doc.Entries = append(doc.Entries, entry)
+	}
+	return marshal(doc)
+}
+
+// 订阅源的最后更新时间
+func (f *Feed) updated() time.Time {
+	updated := f.Updated
+	if updated.IsZero() {
+		for _, item := range f.Items {
+			if t := item.updated(); t.After(updated) {
+				updated = t
+			}
+		}
+	}
+	return updated
+}
+
+// 条目唯一标识
+func (item *Item) id() string {
+	if item.Id != "" {
+		return item.Id
+	}
+	return item.Link
+}
+
+// 条目更新时间
+func (item *Item) updated() time.Time {
+	if item.Updated.IsZero() {
+		return item.Published
+	}
+	return item.Updated
+}
+
+// 编码为带有XML声明的文档
+func marshal(v interface{}) ([]byte, error) {
+	b, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), b...), nil
+}
@@ -0,0 +1,265 @@
+package lib_highlight
+
+import (
+	"bytes"
+	"github.com/gogf/gf/g/encoding/ghtml"
+	"github.com/gogf/gf/g/util/gconv"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// 高亮词法单元，Class为空表示普通文本
+type Token struct {
+	Class string `json:"class"`
+	Value string `json:"value"`
+}
+
+// 代码块渲染选项
+type Options struct {
+	LineNumbers bool        // 是否显示行号
+	Highlight   []LineRange // 需要高亮显示的行号范围列表
+}
+
+// 行号范围，行号从1开始，包含起止行
+type LineRange struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// 词法规则
+type rule struct {
+	pattern   *regexp.Regexp
+	class     string   // 匹配内容的样式类型
+	groups    []string // 按照子匹配分组指定样式类型，"@"开头表示使用对应名称的词法分析器继续分析该分组
+	lineStart bool     // 只在行首(允许缩进)匹配
+	wordStart bool     // 只在行首或者空白字符之后匹配
+}
+
+// 基于规则的词法分析器，在每个位置按照顺序尝试规则，第一个匹配的规则生效
+type lexer struct {
+	rules []rule
+}
+
+var (
+	// 语言名称 => 词法分析器
+	lexers = make(map[string]*lexer)
+	// 语言别名 => 语言名称
+	aliases = make(map[string]string)
+)
+
+// 判断是否支持指定语言的语法高亮
+func Supported(lang string) bool {
+	return getLexer(lang) != nil
+}
+
+// 解析代码块信息字符串，格式为: 语言{选项}，选项使用逗号分隔，支持行号范围及linenos，
+// 例如: go{3-5}、go{1,3-5,linenos}
+func ParseInfo(info string) (lang string, options Options) {
+	info = strings.TrimSpace(info)
+	lang = info
+	if i := strings.IndexByte(info, '{'); i >= 0 {
+		lang = strings.TrimSpace(info[:i])
+		spec := strings.TrimRight(info[i+1:], "}")
+		for _, item := range strings.FieldsFunc(spec, func(r rune) bool {
+			return r == ',' || r == ' '
+		}) {
+			switch {
+			case item == "linenos" || item == "numbers":
+				options.LineNumbers = true
+			case strings.Contains(item, "-"):
+				array := strings.SplitN(item, "-", 2)
+				// 只保存范围，不展开为行号列表，避免超大范围占用内存
+				start, end := gconv.Int(array[0]), gconv.Int(array[1])
+				if start > 0 && start <= end {
+					options.Highlight = append(options.Highlight, LineRange{Start: start, End: end})
+				}
+			default:
+				if n := gconv.Int(item); n > 0 {
+					options.Highlight = append(options.Highlight, LineRange{Start: n, End: n})
+				}
+			}
+		}
+	}
+	return strings.ToLower(lang), options
+}
+
+// 判断指定行号是否需要高亮显示
+func (o Options) IsHighlighted(line int) bool {
+	for _, r := range o.Highlight {
+		if line >= r.Start && line <= r.End {
+			return true
+		}
+	}
+	return false
+}
+
+// 将代码切分为高亮词法单元，不支持的语言返回一个普通文本单元
+func Tokenize(code string, lang string) []Token {
+	if l := getLexer(lang); l != nil {
+		return l.tokenize(code)
+	}
+	return []Token{{Value: code}}
+}
+
+// 将代码渲染为带有样式类的HTML，每行使用<span class="line">包裹，
+// 高亮行添加hl样式，开启行号时每行以<span class="ln">行号</span>开头
+func Render(code string, lang string, options Options) string {
+	code   = strings.TrimSuffix(code, "\n")
+	buffer := bytes.NewBuffer(nil)
+	if options.LineNumbers {
+		buffer.WriteString(`<pre class="highlight linenos"><code`)
+	} else {
+		buffer.WriteString(`<pre class="highlight"><code`)
+	}
+	if lang != "" {
+		buffer.WriteString(` class="language-` + ghtml.SpecialChars(lang) + `"`)
+	}
+	buffer.WriteString(">")
+	line     := 1
+	openLine := func() {
+		if options.IsHighlighted(line) {
+			buffer.WriteString(`<span class="line hl">`)
+		} else {
+			buffer.WriteString(`<span class="line">`)
+		}
+		if options.LineNumbers {
+			buffer.WriteString(`<span class="ln">` + gconv.String(line) + `</span>`)
+		}
+	}
+	openLine()
+	for _, token := range Tokenize(code, lang) {
+		for i, part := range strings.Split(token.Value, "\n") {
+			if i > 0 {
+				buffer.WriteString("\n</span>")
+				line++
+				openLine()
+			}
+			if part == "" {
+				continue
+			}
+			if token.Class == "" {
+				buffer.WriteString(ghtml.SpecialChars(part))
+			} else {
+				buffer.WriteString(`<span class="hl-` + token.Class + `">` + ghtml.SpecialChars(part) + `</span>`)
+			}
+		}
+	}
+	buffer.WriteString("\n</span></code></pre>\n")
+	return buffer.String()
+}
+
+// 注册语言词法分析器
+func register(names []string, rules ...rule) {
+	lexers[names[0]] = &lexer{rules: rules}
+	for _, name := range names {
+		aliases[name] = names[0]
+	}
+}
+
+// 根据语言名称或者别名获得词法分析器
+func getLexer(lang string) *lexer {
+	if name, ok := aliases[strings.ToLower(lang)]; ok {
+		return lexers[name]
+	}
+	return nil
+}
+
+// 创建词法规则
+func newRule(pattern string, class string) rule {
+	return rule{
+		pattern: regexp.MustCompile(`\A(?:` + pattern + `)`),
+		class:   class,
+	}
+}
+
+// 创建按照子匹配分组指定样式的词法规则，分组需要覆盖完整的匹配内容
+func newGroupRule(pattern string, groups ...string) rule {
+	return rule{
+		pattern: regexp.MustCompile(`\A(?:` + pattern + `)`),
+		groups:  groups,
+	}
+}
+
+// 设置规则只在行首(允许缩进)匹配
+func (r rule) atLineStart() rule {
+	r.lineStart = true
+	return r
+}
+
+// 设置规则只在行首或者空白字符之后匹配
+func (r rule) atWordStart() rule {
+	r.wordStart = true
+	return r
+}
+
+// 判断规则是否可以在指定位置匹配
+func (r rule) applicable(src string, pos int) bool {
+	if r.lineStart {
+		i := pos - 1
+		for i >= 0 && (src[i] == ' ' || src[i] == '\t') {
+			i--
+		}
+		return i < 0 || src[i] == '\n'
+	}
+	if r.wordStart {
+		return pos == 0 || strings.IndexByte(" \t\r\n", src[pos-1]) >= 0
+	}
+	return true
+}
+
+// 执行词法分析
+func (l *lexer) tokenize(src string) []Token {
+	tokens := make([]Token, 0)
+	emit   := func(class, value string) {
+		if value == "" {
+			return
+		}
+		// 合并相邻的同类型单元
+		if n := len(tokens); n > 0 && tokens[n-1].Class == class {
+			tokens[n-1].Value += value
+			return
+		}
+		tokens = append(tokens, Token{Class: class, Value: value})
+	}
+	for pos := 0; pos < len(src); {
+		matched := false
+		for _, r := range l.rules {
+			if !r.applicable(src, pos) {
+				continue
+			}
+			m := r.pattern.FindStringSubmatchIndex(src[pos:])
+			if m == nil || m[1] == 0 {
+				continue
+			}
+			if r.groups == nil {
+				emit(r.class, src[pos:pos+m[1]])
+			} else {
+				for i, class := range r.groups {
+					start, end := m[2*i+2], m[2*i+3]
+					if start < 0 {
+						continue
+					}
+					value := src[pos+start : pos+end]
+					if strings.HasPrefix(class, "@") {
+						for _, token := range getLexer(class[1:]).tokenize(value) {
+							emit(token.Class, token.Value)
+						}
+					} else {
+						emit(class, value)
+					}
+				}
+			}
+			pos    += m[1]
+			matched = true
+			break
+		}
+		// 没有规则匹配时作为普通文本处理
+		if !matched {
+			_, size := utf8.DecodeRuneInString(src[pos:])
+			emit("", src[pos:pos+size])
+			pos += size
+		}
+	}
+	return tokens
+}
@@ -0,0 +1,126 @@
+package lib_highlight
+
+// 通用规则
+var (
+	ruleSpace      = newRule(`[ \t\r\n]+`, "")
+	ruleIdentifier = newRule(`[A-Za-z_][A-Za-z0-9_]*`, "")
+	ruleNumber     = newRule(`0[xX][0-9a-fA-F_]+|0[bB][01_]+|\d[\d_]*(?:\.\d+)?(?:[eE][+-]?\d+)?`, "number")
+	ruleFunction   = newGroupRule(`([A-Za-z_][A-Za-z0-9_]*)(\s*)(\()`, "function", "", "punct")
+	ruleDqString   = newRule(`"(?:\\.|[^"\\\n])*"`, "string")
+	ruleSqString   = newRule(`'(?:\\.|[^'\\\n])*'`, "string")
+	ruleHashLine   = newRule(`#[^\n]*`, "comment")
+)
+
+func init() {
+	// Go
+	register([]string{"go", "golang"},
+		ruleSpace,
+		newRule(`//[^\n]*|/\*[\s\S]*?\*/`, "comment"),
+		newRule("`[^`]*`", "string"),
+		ruleDqString,
+		ruleSqString,
+		newRule(`(?:break|case|chan|const|continue|default|defer|else|fallthrough|for|func|go|goto|if|import|interface|map|package|range|return|select|struct|switch|type|var)\b`, "keyword"),
+		newRule(`(?:true|false|nil|iota)\b`, "literal"),
+		newRule(`(?:any|bool|byte|complex64|complex128|error|float32|float64|int|int8|int16|int32|int64|rune|string|uint|uint8|uint16|uint32|uint64|uintptr)\b`, "type"),
+		newGroupRule(`(append|cap|close|complex|copy|delete|imag|len|make|new|panic|print|println|real|recover)(\s*)(\()`, "builtin", "", "punct"),
+		ruleFunction,
+		ruleIdentifier,
+		ruleNumber,
+		newRule(`[-+*/%&|^<>=!:;.,(){}\[\]~]`, "punct"),
+	)
+
+	// JSON
+	register([]string{"json", "jsonc"},
+		ruleSpace,
+		newRule(`//[^\n]*|/\*[\s\S]*?\*/`, "comment"),
+		newGroupRule(`("(?:\\.|[^"\\\n])*")(\s*)(:)`, "key", "", "punct"),
+		ruleDqString,
+		newRule(`(?:true|false|null)\b`, "literal"),
+		newRule(`-?\d+(?:\.\d+)?(?:[eE][+-]?\d+)?`, "number"),
+		newRule(`[{}\[\],:]`, "punct"),
+	)
+
+	// YAML
+	register([]string{"yaml", "yml"},
+		ruleSpace,
+		ruleHashLine.atWordStart(),
+		newRule(`(?:---|\.\.\.)[ \t]*$|(?:---|\.\.\.)[ \t]*\n`, "meta").atLineStart(),
+		newGroupRule(`(-[ \t]+)("(?:\\.|[^"\\\n])*"|'[^'\n]*'|[^\s#'"\-:][^:\n]*?|-[^\s:][^:\n]*?)([ \t]*)(:)([ \t]|\n|$)`, "punct", "key", "", "punct", "").atLineStart(),
+		newGroupRule(`("(?:\\.|[^"\\\n])*"|'[^'\n]*'|[^\s#'"\-:][^:\n]*?|-[^\s:][^:\n]*?)([ \t]*)(:)([ \t]|\n|$)`, "key", "", "punct", "").atLineStart(),
+		newRule(`-[ \t]`, "punct").atLineStart(),
+		ruleDqString,
+		newRule(`'[^'\n]*'`, "string"),
+		newRule(`[&*][\w-]+`, "variable").atWordStart(),
+		newRule(`![\w!/.-]*`, "type").atWordStart(),
+		newRule(`(?:true|false|yes|no|on|off|null|True|False|Yes|No|On|Off|Null|TRUE|FALSE|NULL|~)[ \t]*(?:\n|$)`, "literal").atWordStart(),
+		newRule(`-?\d[\d_]*(?:\.\d+)?(?:[eE][+-]?\d+)?[ \t]*(?:\n|$)`, "number").atWordStart(),
+		newRule(`[|>][-+]?[ \t]*(?:\n|$)`, "punct").atWordStart(),
+		newRule(`[\[\]{},]`, "punct"),
+		newRule(`[^\s#'"\[\]{},]+`, ""),
+	)
+
+	// TOML
+	register([]string{"toml"},
+		ruleSpace,
+		ruleHashLine,
+		newRule(`\[\[?[^\]\n]+\]\]?`, "section").atLineStart(),
+		newGroupRule(`([\w.\-]+|"(?:\\.|[^"\\\n])*"|'[^'\n]*')([ \t]*)(=)`, "key", "", "punct").atLineStart(),
+		newRule(`"""[\s\S]*?"""|'''[\s\S]*?'''`, "string"),
+		ruleDqString,
+		newRule(`'[^'\n]*'`, "string"),
+		newRule(`\d{4}-\d{2}-\d{2}(?:[T ]\d{2}:\d{2}:\d{2}(?:\.\d+)?(?:Z|[+-]\d{2}:\d{2})?)?`, "number"),
+		newRule(`(?:true|false)\b`, "literal"),
+		newRule(`[+-]?(?:inf|nan)\b|[+-]?0[xob][0-9a-fA-F_]+|[+-]?\d[\d_]*(?:\.\d+)?(?:[eE][+-]?\d+)?`, "number"),
+		newRule(`[\[\]{},=.]`, "punct"),
+		ruleIdentifier,
+	)
+
+	// SQL
+	register([]string{"sql", "mysql", "sqlite", "pgsql", "postgresql"},
+		ruleSpace,
+		newRule(`--[^\n]*|/\*[\s\S]*?\*/`, "comment"),
+		ruleHashLine.atLineStart(),
+		newRule(`'(?:''|\\.|[^'\\])*'`, "string"),
+		newRule(`"(?:\\.|[^"\\])*"|`+"`[^`]*`", "variable"),
+		newRule(`(?i)(?:select|from|where|and|or|not|insert|into|values|update|set|delete|create|table|drop|alter|add|column|index|primary|key|foreign|references|unique|default|is|in|like|between|join|left|right|inner|outer|full|cross|on|as|group|by|order|having|limit|offset|union|all|distinct|case|when|then|else|end|exists|if|begin|commit|rollback|transaction|asc|desc|auto_increment|autoincrement|engine|charset|collate|comment|database|use|show|grant|revoke|view|trigger|procedure|function|returns|return|replace|truncate|with|constraint|check|explain|describe)\b`, "keyword"),
+		newRule(`(?i)(?:int|integer|tinyint|smallint|mediumint|bigint|decimal|numeric|float|double|real|bit|bool|boolean|char|varchar|text|tinytext|mediumtext|longtext|blob|longblob|date|time|datetime|timestamp|year|json|enum|unsigned|serial)\b`, "type"),
+		newRule(`(?i)(?:null|true|false)\b`, "literal"),
+		ruleFunction,
+		ruleIdentifier,
+		ruleNumber,
+		newRule(`[@:?][\w]*`, "variable"),
+		newRule(`[-+*/%&|^<>=!~;.,()]`, "punct"),
+	)
+
+	// Shell
+	register([]string{"shell", "sh", "bash", "zsh", "console", "shell-session"},
+		ruleSpace,
+		ruleHashLine.atWordStart(),
+		newRule(`[$#>][ \t]`, "meta").atLineStart(),
+		newRule(`'[^']*'`, "string"),
+		newRule(`"(?:\\.|[^"\\])*"`, "string"),
+		newRule(`\$\{[^}\n]*\}|\$\(|\$[A-Za-z_][A-Za-z0-9_]*|\$[0-9@#?*$!-]`, "variable"),
+		newRule(`(?:if|then|else|elif|fi|for|while|until|do|done|case|esac|in|function|return|export|local|select|break|continue|exit|readonly|declare|unset|shift|trap)(?:\b|$)`, "keyword").atWordStart(),
+		newRule(`(?:echo|printf|cd|pwd|ls|cp|mv|rm|mkdir|rmdir|cat|grep|sed|awk|find|xargs|sudo|chmod|chown|ln|tar|curl|wget|git|go|gf|docker|make|source|kill|ps|touch|head|tail|sort|uniq|wc|tee|env|which|apt|apt-get|yum|brew|npm|systemctl|nohup|ssh|scp)(?:[ \t]|\n|$)`, "builtin").atWordStart(),
+		newRule(`--?[A-Za-z][\w-]*`, "attr").atWordStart(),
+		newRule(`[|&;<>()]+|=`, "punct"),
+		newRule(`\d+(?:[ \t]|\n|$)`, "number").atWordStart(),
+		newRule(`[^\s'"$#|&;<>()=]+`, ""),
+	)
+
+	// HTML
+	register([]string{"html", "htm", "xml", "xhtml", "svg", "vue"},
+		newRule(`<!--[\s\S]*?-->`, "comment"),
+		newRule(`<![^>]*>|<\?[\s\S]*?\?>`, "meta"),
+		newGroupRule(`(</?)([A-Za-z][\w:.-]*)([^<>]*?)(/?>)`, "punct", "tag", "@html-attrs", "punct"),
+		newRule(`&#?\w+;`, "literal"),
+		newRule(`[^<&]+`, ""),
+	)
+	// HTML标签属性，仅在HTML标签内部使用
+	register([]string{"html-attrs"},
+		ruleSpace,
+		newRule(`"[^"]*"|'[^']*'`, "string"),
+		newRule(`[A-Za-z_:@#][\w:.@#-]*`, "attr"),
+		newRule(`=`, "punct"),
+	)
+}
@@ -0,0 +1,65 @@
+package lib_highlight
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseInfo(t *testing.T) {
+	cases := []struct {
+		info    string
+		lang    string
+		options Options
+	}{
+		{"go", "go", Options{}},
+		{" Go ", "go", Options{}},
+		{"go{3}", "go", Options{Highlight: []LineRange{{3, 3}}}},
+		{"go {3-5}", "go", Options{Highlight: []LineRange{{3, 5}}}},
+		{"go{1,3-5,linenos}", "go", Options{LineNumbers: true, Highlight: []LineRange{{1, 1}, {3, 5}}}},
+		{"go{numbers}", "go", Options{LineNumbers: true}},
+		{"go{5-3,0,-1,x}", "go", Options{}},
+		{"go{1-2000000000}", "go", Options{Highlight: []LineRange{{1, 2000000000}}}},
+		{"{2}", "", Options{Highlight: []LineRange{{2, 2}}}},
+	}
+	for _, c := range cases {
+		lang, options := ParseInfo(c.info)
+		if lang != c.lang || !reflect.DeepEqual(options, c.options) {
+			t.Errorf("ParseInfo(%q) = %q, %+v, want %q, %+v", c.info, lang, options, c.lang, c.options)
+		}
+	}
+}
+
+func TestIsHighlighted(t *testing.T) {
+	options := Options{Highlight: []LineRange{{1, 1}, {3, 5}}}
+	cases := []struct {
+		line int
+		want bool
+	}{
+		{0, false}, {1, true}, {2, false}, {3, true}, {5, true}, {6, false},
+	}
+	for _, c := range cases {
+		if got := options.IsHighlighted(c.line); got != c.want {
+			t.Errorf("IsHighlighted(%d) = %v, want %v", c.line, got, c.want)
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	_, options := ParseInfo("go{2,linenos}")
+	html := Render("a := 1\nb := \"<x>\"\n", "go", options)
+	cases := []string{
+		`<pre class="highlight linenos"><code class="language-go">`,
+		`<span class="line"><span class="ln">1</span>`,
+		`<span class="line hl"><span class="ln">2</span>`,
+		`&lt;x&gt;`,
+	}
+	for _, want := range cases {
+		if !strings.Contains(html, want) {
+			t.Errorf("Render() = %s, want containing %s", html, want)
+		}
+	}
+	if strings.Contains(html, `<span class="ln">3</span>`) {
+		t.Errorf("Render() renders trailing empty line: %s", html)
+	}
+}
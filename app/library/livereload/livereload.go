@@ -0,0 +1,101 @@
+package lib_livereload
+
+import (
+	"gf-blog/app/library/document"
+	"github.com/gogf/gf/g/net/ghttp"
+	"github.com/gogf/gf/g/os/glog"
+	"strings"
+	"sync"
+)
+
+// 客户端连接
+type Client struct {
+	path string            // 客户端当前打开的文档路径
+	ws   *ghttp.WebSocket  // WebSocket连接对象
+	mu   sync.Mutex        // 写互斥锁，WebSocket连接不支持并发写入
+}
+
+// 推送给客户端的事件消息
+type Message struct {
+	Event string `json:"event"`
+	Path  string `json:"path"`
+}
+
+var (
+	// 文档路径 => 客户端集合
+	clients   = make(map[string]map[*Client]struct{})
+	clientsMu = sync.RWMutex{}
+)
+
+// 注册客户端连接，path为客户端当前打开的文档路径
+func Register(path string, ws *ghttp.WebSocket) *Client {
+	client := &Client{
+		path: normalize(path),
+		ws:   ws,
+	}
+	clientsMu.Lock()
+	defer clientsMu.Unlock()
+	if _, ok := clients[client.path]; !ok {
+		clients[client.path] = make(map[*Client]struct{})
+	}
+	clients[client.path][client] = struct{}{}
+	return client
+}
+
+// 注销客户端连接
+func Unregister(client *Client) {
+	clientsMu.Lock()
+	defer clientsMu.Unlock()
+	if m, ok := clients[client.path]; ok {
+		delete(m, client)
+		if len(m) == 0 {
+			delete(clients, client.path)
+		}
+	}
+}
+
+// 通知打开了指定文档的客户端文档已变更，包括回退显示该文档的其他语言页面，
+// 任意版本及语言的菜单变更时通知所有客户端
+func Notify(paths []string) {
+	targets := make([]*Client, 0)
+	clientsMu.RLock()
+	for _, path := range paths {
+		path   = normalize(path)
+		isMenu := lib_document.GetMenuPath(path) == path
+		for p, m := range clients {
+			if p != path && !isMenu && !isFallbackOf(p, path) {
+				continue
+			}
+			for client := range m {
+				targets = append(targets, client)
+			}
+		}
+	}
+	clientsMu.RUnlock()
+	for _, client := range targets {
+		if err := client.send(Message{Event: "changed", Path: client.path}); err != nil {
+			glog.Cat("livereload").Printfln("push to %s failed: %v", client.path, err)
+		}
+	}
+}
+
+// 判断客户端打开的文档是否为未翻译文档，回退显示的是指定路径的默认语言文档
+func isFallbackOf(clientPath string, path string) bool {
+	return lib_document.GetFallbackPath(clientPath) == path && lib_document.GetMarkdown(clientPath) == ""
+}
+
+// 推送消息到客户端
+func (c *Client) send(message Message) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ws.WriteJSON(message)
+}
+
+// 文档路径标准化，去掉首尾的"/"，空路径为index
+func normalize(path string) string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return "index"
+	}
+	return path
+}
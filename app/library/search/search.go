@@ -0,0 +1,266 @@
+package lib_search
+
+import (
+	"gf-blog/app/library/tokenizer"
+	"math"
+	"sort"
+	"sync"
+)
+
+const (
+	// BM25 词频饱和参数
+	bm25K1 = 1.2
+	// BM25 文档长度归一化参数
+	bm25B  = 0.75
+)
+
+// 检索结果项
+type Result struct {
+	Path  string  `json:"path"`
+	Score float64 `json:"score"`
+}
+
+// 内存倒排索引，并发安全
+type Index struct {
+	mu        sync.RWMutex
+	tokenizer lib_tokenizer.Tokenizer     // 分词器，索引及查询共用
+	docs      map[string]*document        // 文档ID => 文档信息
+	postings  map[string]map[string][]int // 词项 => 文档ID => 词项位置列表
+	totalLen  int                         // 索引中所有文档的词项总数
+}
+
+// 索引中的文档信息
+type document struct {
+	length int      // 文档词项数量
+	terms  []string // 文档包含的词项(去重)，用于删除索引
+}
+
+// 创建一个空的倒排索引，可以指定分词器，默认使用lib_tokenizer.Default()
+func New(tokenizer ...lib_tokenizer.Tokenizer) *Index {
+	idx := &Index{
+		tokenizer: lib_tokenizer.Default(),
+		docs:      make(map[string]*document),
+		postings:  make(map[string]map[string][]int),
+	}
+	if len(tokenizer) > 0 && tokenizer[0] != nil {
+		idx.tokenizer = tokenizer[0]
+	}
+	return idx
+}
+
+// 获得索引使用的分词器
+func (idx *Index) Tokenizer() lib_tokenizer.Tokenizer {
+	return idx.tokenizer
+}
+
+// 添加文档到索引中，文档ID已存在时覆盖原有索引
+func (idx *Index) Add(id string, content string) {
+	groups    := idx.tokenize(content)
+	positions := make(map[string][]int)
+	for i, group := range groups {
+		for _, token := range group {
+			// 同一位置上的重复词项(如单字词项)只记录一次
+			if list := positions[token]; len(list) == 0 || list[len(list)-1] != i {
+				positions[token] = append(list, i)
+			}
+		}
+	}
+	doc := &document{
+		length: len(groups),
+		terms:  make([]string, 0, len(positions)),
+	}
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.remove(id)
+	for term, list := range positions {
+		m, ok := idx.postings[term]
+		if !ok {
+			m = make(map[string][]int)
+			idx.postings[term] = m
+		}
+		m[id] = list
+		doc.terms = append(doc.terms, term)
+	}
+	idx.docs[id] = doc
+	idx.totalLen += doc.length
+}
+
+// 将文档内容切分为索引词项，每个元素为同一位置上的词项列表。
+// 分词器实现lib_tokenizer.IndexTokenizer接口时同时索引扩展词项，文档长度只计算位置数量
+func (idx *Index) tokenize(content string) [][]string {
+	if tokenizer, ok := idx.tokenizer.(lib_tokenizer.IndexTokenizer); ok {
+		groups := tokenizer.TokenizeIndex(content)
+		result := make([][]string, len(groups))
+		for i, group := range groups {
+			result[i] = make([]string, len(group))
+			for j, token := range group {
+				result[i][j] = token.Text
+			}
+		}
+		return result
+	}
+	tokens := idx.tokenizer.Tokenize(content)
+	result := make([][]string, len(tokens))
+	for i, token := range tokens {
+		result[i] = []string{token}
+	}
+	return result
+}
+
+// 从索引中删除文档
+func (idx *Index) Remove(id string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.remove(id)
+}
+
+// 判断文档是否已在索引中
+func (idx *Index) Contains(id string) bool {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	_, ok := idx.docs[id]
+	return ok
+}
+
+// 索引中的文档数量
+func (idx *Index) Len() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return len(idx.docs)
+}
+
+// 执行检索，返回按照相关度(BM25)从高到低排序的结果列表。
+// 查询语法：空格分隔的多个词项为AND关系，使用OR或者|分隔为OR关系，使用双引号包含的内容为短语查询。
+func (idx *Index) Search(query string) []Result {
+	clauses := parseQuery(query, idx.tokenizer)
+	if len(clauses) == 0 {
+		return nil
+	}
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	// 各子句之间为OR关系，结果取并集
+	matched := make(map[string]struct{})
+	for _, clause := range clauses {
+		for id := range idx.matchClause(clause) {
+			matched[id] = struct{}{}
+		}
+	}
+	if len(matched) == 0 {
+		return nil
+	}
+	// 参与打分的词项(去重)
+	terms := make(map[string]struct{})
+	for _, clause := range clauses {
+		for _, item := range clause {
+			for _, term := range item {
+				terms[term] = struct{}{}
+			}
+		}
+	}
+	results := make([]Result, 0, len(matched))
+	for id := range matched {
+		score := 0.0
+		for term := range terms {
+			score += idx.bm25(term, id)
+		}
+		results = append(results, Result{
+			Path:  id,
+			Score: score,
+		})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Path < results[j].Path
+	})
+	return results
+}
+
+// 删除文档索引，调用端需要加写锁
+func (idx *Index) remove(id string) {
+	doc, ok := idx.docs[id]
+	if !ok {
+		return
+	}
+	for _, term := range doc.terms {
+		if m, ok := idx.postings[term]; ok {
+			delete(m, id)
+			if len(m) == 0 {
+				delete(idx.postings, term)
+			}
+		}
+	}
+	idx.totalLen -= doc.length
+	delete(idx.docs, id)
+}
+
+// 检索满足子句所有查询项(AND关系)的文档集合，调用端需要加读锁
+func (idx *Index) matchClause(clause []queryItem) map[string]struct{} {
+	var result map[string]struct{}
+	for _, item := range clause {
+		docs := idx.matchItem(item)
+		if result == nil {
+			result = docs
+		} else {
+			for id := range result {
+				if _, ok := docs[id]; !ok {
+					delete(result, id)
+				}
+			}
+		}
+		if len(result) == 0 {
+			return nil
+		}
+	}
+	return result
+}
+
+// 检索满足单个查询项的文档集合，多个词项的查询项要求词项在文档中连续出现(短语查询)
+func (idx *Index) matchItem(item queryItem) map[string]struct{} {
+	result := make(map[string]struct{})
+	first  := idx.postings[item[0]]
+	for id, positions := range first {
+		if len(item) == 1 {
+			result[id] = struct{}{}
+			continue
+		}
+		for _, pos := range positions {
+			if idx.matchPhraseAt(item, id, pos) {
+				result[id] = struct{}{}
+				break
+			}
+		}
+	}
+	return result
+}
+
+// 判断短语是否从文档的指定位置开始出现
+func (idx *Index) matchPhraseAt(item queryItem, id string, pos int) bool {
+	for i := 1; i < len(item); i++ {
+		positions := idx.postings[item[i]][id]
+		n := sort.SearchInts(positions, pos+i)
+		if n >= len(positions) || positions[n] != pos+i {
+			return false
+		}
+	}
+	return true
+}
+
+// 计算词项对于指定文档的BM25得分
+func (idx *Index) bm25(term string, id string) float64 {
+	m, ok := idx.postings[term]
+	if !ok {
+		return 0
+	}
+	tf := float64(len(m[id]))
+	if tf == 0 {
+		return 0
+	}
+	n      := float64(len(idx.docs))
+	df     := float64(len(m))
+	idf    := math.Log(1 + (n-df+0.5)/(df+0.5))
+	avgLen := float64(idx.totalLen) / n
+	docLen := float64(idx.docs[id].length)
+	return idf * tf * (bm25K1 + 1) / (tf + bm25K1*(1-bm25B+bm25B*docLen/avgLen))
+}
@@ -0,0 +1,51 @@
+package lib_search
+
+import (
+	"sort"
+)
+
+// 导出的索引数据，用于客户端(例如静态站点)使用BM25进行检索
+type IndexData struct {
+	K1      float64             `json:"k1"`      // BM25 词频饱和参数
+	B       float64             `json:"b"`       // BM25 文档长度归一化参数
+	AvgLen  float64             `json:"avgLen"`  // 文档平均词项数量
+	Ids     []string            `json:"ids"`     // 文档ID列表(按照ID排序)
+	Lengths []int               `json:"lengths"` // 与文档ID列表对应的文档词项数量
+	Terms   map[string][][2]int `json:"terms"`   // 词项 => [文档序号, 词频]列表
+}
+
+// 导出索引数据，文档使用在Ids中的序号表示
+func (idx *Index) Export() *IndexData {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	data := &IndexData{
+		K1:      bm25K1,
+		B:       bm25B,
+		Ids:     make([]string, 0, len(idx.docs)),
+		Lengths: make([]int, 0, len(idx.docs)),
+		Terms:   make(map[string][][2]int, len(idx.postings)),
+	}
+	for id := range idx.docs {
+		data.Ids = append(data.Ids, id)
+	}
+	sort.Strings(data.Ids)
+	seqs := make(map[string]int, len(data.Ids))
+	for i, id := range data.Ids {
+		seqs[id] = i
+		data.Lengths = append(data.Lengths, idx.docs[id].length)
+	}
+	if len(idx.docs) > 0 {
+		data.AvgLen = float64(idx.totalLen) / float64(len(idx.docs))
+	}
+	for term, m := range idx.postings {
+		list := make([][2]int, 0, len(m))
+		for id, positions := range m {
+			list = append(list, [2]int{seqs[id], len(positions)})
+		}
+		sort.Slice(list, func(i, j int) bool {
+			return list[i][0] < list[j][0]
+		})
+		data.Terms[term] = list
+	}
+	return data
+}
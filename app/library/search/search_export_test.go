@@ -0,0 +1,22 @@
+package lib_search
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExport(t *testing.T) {
+	idx := New()
+	idx.Add("b", "server server")
+	idx.Add("a", "server config")
+	data := idx.Export()
+	if !reflect.DeepEqual(data.Ids, []string{"a", "b"}) {
+		t.Errorf("Ids = %q", data.Ids)
+	}
+	if !reflect.DeepEqual(data.Lengths, []int{2, 2}) || data.AvgLen != 2 {
+		t.Errorf("Lengths = %v, AvgLen = %v", data.Lengths, data.AvgLen)
+	}
+	if want := [][2]int{{0, 1}, {1, 2}}; !reflect.DeepEqual(data.Terms["server"], want) {
+		t.Errorf("Terms[server] = %v, want %v", data.Terms["server"], want)
+	}
+}
@@ -0,0 +1,81 @@
+package lib_search
+
+import (
+	"gf-blog/app/library/tokenizer"
+	"sort"
+)
+
+// 文本中的匹配区间，位置为字符(rune)偏移量，左闭右开
+type Range [2]int
+
+// 获得查询语句中的所有词项(去重)
+func (idx *Index) QueryTerms(query string) []string {
+	terms := make([]string, 0)
+	exist := make(map[string]struct{})
+	for _, clause := range parseQuery(query, idx.tokenizer) {
+		for _, item := range clause {
+			for _, term := range item {
+				if _, ok := exist[term]; !ok {
+					exist[term] = struct{}{}
+					terms = append(terms, term)
+				}
+			}
+		}
+	}
+	return terms
+}
+
+// 查找文本中与查询词项匹配的区间列表，重叠或者相邻的区间会被合并。
+// 索引分词器需要实现lib_tokenizer.OffsetTokenizer接口，否则返回空。
+func (idx *Index) Highlight(text string, terms []string) []Range {
+	tokenizer, ok := idx.tokenizer.(lib_tokenizer.OffsetTokenizer)
+	if !ok || len(terms) == 0 {
+		return nil
+	}
+	set := make(map[string]struct{}, len(terms))
+	for _, term := range terms {
+		set[term] = struct{}{}
+	}
+	ranges := make([]Range, 0)
+	for _, token := range highlightTokens(tokenizer, text) {
+		if _, ok := set[token.Text]; ok {
+			ranges = append(ranges, Range{token.Start, token.End})
+		}
+	}
+	return mergeRanges(ranges)
+}
+
+// 获得用于匹配高亮的词项列表，与索引时一致，包括扩展词项
+func highlightTokens(tokenizer lib_tokenizer.OffsetTokenizer, text string) []lib_tokenizer.Token {
+	indexTokenizer, ok := tokenizer.(lib_tokenizer.IndexTokenizer)
+	if !ok {
+		return tokenizer.TokenizeOffsets(text)
+	}
+	tokens := make([]lib_tokenizer.Token, 0)
+	for _, group := range indexTokenizer.TokenizeIndex(text) {
+		tokens = append(tokens, group...)
+	}
+	return tokens
+}
+
+// 合并重叠或者相邻的区间
+func mergeRanges(ranges []Range) []Range {
+	if len(ranges) < 2 {
+		return ranges
+	}
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i][0] < ranges[j][0]
+	})
+	merged := []Range{ranges[0]}
+	for _, r := range ranges[1:] {
+		last := &merged[len(merged)-1]
+		if r[0] <= last[1] {
+			if r[1] > last[1] {
+				last[1] = r[1]
+			}
+		} else {
+			merged = append(merged, r)
+		}
+	}
+	return merged
+}
@@ -0,0 +1,28 @@
+package lib_search
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestHighlight(t *testing.T) {
+	idx   := New()
+	cases := []struct {
+		text  string
+		query string
+		want  []Range
+	}{
+		{"the web server", "server", []Range{{8, 14}}},
+		{"the web server", "web server", []Range{{4, 7}, {8, 14}}},
+		{"数据库连接", "数据库 连接", []Range{{0, 5}}},
+		{"使用数据库进行查询", "数据", []Range{{2, 4}}},
+		{"使用数据库进行查询", "数据库 查询", []Range{{2, 5}, {7, 9}}},
+		{"nothing here", "server", []Range{}},
+	}
+	for _, c := range cases {
+		got := idx.Highlight(c.text, idx.QueryTerms(c.query))
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("Highlight(%q, %q) = %v, want %v", c.text, c.query, got, c.want)
+		}
+	}
+}
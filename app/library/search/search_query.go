@@ -0,0 +1,69 @@
+package lib_search
+
+import (
+	"gf-blog/app/library/tokenizer"
+	"strings"
+	"unicode"
+)
+
+// 查询项，由一个或者多个连续的词项组成
+type queryItem []string
+
+// 解析查询语句，返回以OR关系组合的子句列表，每个子句由以AND关系组合的查询项组成
+func parseQuery(query string, tokenizer lib_tokenizer.Tokenizer) [][]queryItem {
+	clauses := make([][]queryItem, 0)
+	clause  := make([]queryItem, 0)
+	flush   := func() {
+		if len(clause) > 0 {
+			clauses = append(clauses, clause)
+			clause  = make([]queryItem, 0)
+		}
+	}
+	for _, word := range splitQuery(query) {
+		if !word.quoted && (word.text == "OR" || word.text == "|") {
+			flush()
+			continue
+		}
+		// 单个查询词切分出多个词项时(例如中文)，按照短语处理
+		if tokens := tokenizer.Tokenize(word.text); len(tokens) > 0 {
+			clause = append(clause, queryItem(tokens))
+		}
+	}
+	flush()
+	return clauses
+}
+
+// 查询语句中的单词
+type queryWord struct {
+	text   string
+	quoted bool
+}
+
+// 按照空白字符切分查询语句，双引号包含的内容作为一个整体
+func splitQuery(query string) []queryWord {
+	words  := make([]queryWord, 0)
+	buffer := strings.Builder{}
+	quoted := false
+	flush  := func(isQuoted bool) {
+		if buffer.Len() > 0 {
+			words = append(words, queryWord{
+				text:   buffer.String(),
+				quoted: isQuoted,
+			})
+			buffer.Reset()
+		}
+	}
+	for _, r := range query {
+		switch {
+		case r == '"':
+			flush(quoted)
+			quoted = !quoted
+		case unicode.IsSpace(r) && !quoted:
+			flush(false)
+		default:
+			buffer.WriteRune(r)
+		}
+	}
+	flush(quoted)
+	return words
+}
@@ -0,0 +1,103 @@
+package lib_search
+
+import (
+	"reflect"
+	"testing"
+)
+
+// 创建测试使用的索引
+func newTestIndex() *Index {
+	idx := New()
+	idx.Add("router", "路由注册 router register, the router supports groups")
+	idx.Add("server", "服务配置 server config, the web server listens on a port")
+	idx.Add("db", "数据库 ORM, database config and transactions")
+	idx.Add("cache", "缓存 cache, memory cache for the web server")
+	return idx
+}
+
+// 获得检索结果的文档ID列表
+func resultPaths(results []Result) []string {
+	paths := make([]string, 0, len(results))
+	for _, r := range results {
+		paths = append(paths, r.Path)
+	}
+	return paths
+}
+
+func TestSearch(t *testing.T) {
+	idx   := newTestIndex()
+	cases := []struct {
+		query string
+		want  []string
+	}{
+		{"", []string{}},
+		{"nothing", []string{}},
+		{"router", []string{"router"}},
+		{"Routers", []string{"router"}},
+		{"config", []string{"db", "server"}},
+		{"web server", []string{"server", "cache"}},
+		{"cache OR router", []string{"cache", "router"}},
+		{"cache | database", []string{"cache", "db"}},
+		{`"web server"`, []string{"cache", "server"}},
+		{`"server web"`, []string{}},
+		{"数据库", []string{"db"}},
+		{"数据", []string{"db"}},
+		{"缓", []string{"cache"}},
+	}
+	for _, c := range cases {
+		got := resultPaths(idx.Search(c.query))
+		if !sameSet(got, c.want) {
+			t.Errorf("Search(%q) = %q, want %q", c.query, got, c.want)
+		}
+	}
+}
+
+func TestSearchRanking(t *testing.T) {
+	idx := New()
+	idx.Add("once", "server and some other words in a longer document about many topics")
+	idx.Add("twice", "server server")
+	idx.Add("none", "nothing to see")
+	want := []string{"twice", "once"}
+	if got := resultPaths(idx.Search("server")); !reflect.DeepEqual(got, want) {
+		t.Errorf("Search ranking = %q, want %q", got, want)
+	}
+	// 得分相同时按照文档ID排序，保证结果稳定
+	idx.Add("a", "same words")
+	idx.Add("b", "same words")
+	want = []string{"a", "b"}
+	if got := resultPaths(idx.Search("same")); !reflect.DeepEqual(got, want) {
+		t.Errorf("Search tie = %q, want %q", got, want)
+	}
+}
+
+func TestAddRemove(t *testing.T) {
+	idx := newTestIndex()
+	idx.Add("router", "replaced content")
+	if got := idx.Search("groups"); len(got) != 0 {
+		t.Errorf("Search after replace = %v, want empty", got)
+	}
+	idx.Remove("router")
+	if idx.Contains("router") || idx.Len() != 3 {
+		t.Errorf("Remove failed, len = %d", idx.Len())
+	}
+	if got := idx.Search("replaced"); len(got) != 0 {
+		t.Errorf("Search after remove = %v, want empty", got)
+	}
+}
+
+// 判断两个列表包含的元素是否相同(不考虑顺序)
+func sameSet(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	set := make(map[string]bool, len(a))
+	for _, s := range a {
+		set[s] = true
+	}
+	for _, s := range b {
+		if !set[s] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,135 @@
+package lib_sitemap
+
+import (
+	"encoding/xml"
+	"strconv"
+	"time"
+)
+
+const (
+	// 单个sitemap文件允许的最大URL数量，超过时需要拆分并使用sitemap索引
+	MaxUrls = 50000
+	// sitemap协议的XML命名空间
+	namespace = "http://www.sitemaps.org/schemas/sitemap/0.9"
+)
+
+// sitemap中的URL
+type Url struct {
+	Loc        string    // 页面地址(绝对URL)
+	LastMod    time.Time // 最后修改时间
+	ChangeFreq string    // 更新频率，例如: daily、weekly
+	Priority   float64   // 优先级(0.0-1.0)，为0时不输出
+}
+
+// sitemap索引中的sitemap文件
+type Sitemap struct {
+	Loc     string    // sitemap文件地址(绝对URL)
+	LastMod time.Time // 最后修改时间
+}
+
+type urlSet struct {
+	XMLName xml.Name `xml:"urlset"`
+	Xmlns   string   `xml:"xmlns,attr"`
+	Urls    []urlXml `xml:"url"`
+}
+
+type urlXml struct {
+	Loc        string `xml:"loc"`
+	LastMod    string `xml:"lastmod,omitempty"`
+	ChangeFreq string `xml:"changefreq,omitempty"`
+	Priority   string `xml:"priority,omitempty"`
+}
+
+type sitemapIndex struct {
+	XMLName  xml.Name     `xml:"sitemapindex"`
+	Xmlns    string       `xml:"xmlns,attr"`
+	Sitemaps []sitemapXml `xml:"sitemap"`
+}
+
+type sitemapXml struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// 生成sitemap文件内容
+func Render(urls []Url) ([]byte, error) {
+	set := urlSet{
+		Xmlns: namespace,
+		Urls:  make([]urlXml, len(urls)),
+	}
+	for i, u := range urls {
+		set.Urls[i] = urlXml{
+			Loc:        u.Loc,
+			LastMod:    formatTime(u.LastMod),
+			ChangeFreq: u.ChangeFreq,
+		}
+		if u.Priority > 0 {
+			set.Urls[i].Priority = formatPriority(u.Priority)
+		}
+	}
+	return marshal(set)
+}
+
+// 生成sitemap索引文件内容
+func RenderIndex(sitemaps []Sitemap) ([]byte, error) {
+	index := sitemapIndex{
+		Xmlns:    namespace,
+		Sitemaps: make([]sitemapXml, len(sitemaps)),
+	}
+	for i, s := range sitemaps {
+		index.Sitemaps[i] = sitemapXml{
+			Loc:     s.Loc,
+			LastMod: formatTime(s.LastMod),
+		}
+	}
+	return marshal(index)
+}
+
+// 将URL列表按照MaxUrls拆分为多个分组
+func Split(urls []Url) [][]Url {
+	groups := make([][]Url, 0, len(urls)/MaxUrls+1)
+	for start := 0; start < len(urls); start += MaxUrls {
+		end := start + MaxUrls
+		if end > len(urls) {
+			end = len(urls)
+		}
+		groups = append(groups, urls[start:end])
+	}
+	return groups
+}
+
+// 获得URL列表中最新的修改时间
+func LastMod(urls []Url) time.Time {
+	last := time.Time{}
+	for _, u := range urls {
+		if u.LastMod.After(last) {
+			last = u.LastMod
+		}
+	}
+	return last
+}
+
+// 使用W3C Datetime格式输出时间
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// 输出一位小数的优先级
+func formatPriority(p float64) string {
+	if p > 1 {
+		p = 1
+	}
+	return strconv.FormatFloat(p, 'f', 1, 64)
+}
+
+// 编码为带有XML声明的文档
+func marshal(v interface{}) ([]byte, error) {
+	b, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), b...), nil
+}
@@ -0,0 +1,100 @@
+package lib_sitemap
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// 创建指定数量的URL列表
+func newUrls(n int) []Url {
+	urls := make([]Url, n)
+	for i := range urls {
+		urls[i] = Url{Loc: "https://example.com/" + string(rune('a'+i%26))}
+	}
+	return urls
+}
+
+func TestSplit(t *testing.T) {
+	cases := []struct {
+		count int
+		sizes []int
+	}{
+		{0, []int{}},
+		{1, []int{1}},
+		{MaxUrls, []int{MaxUrls}},
+		{MaxUrls + 1, []int{MaxUrls, 1}},
+		{MaxUrls*2 + 10, []int{MaxUrls, MaxUrls, 10}},
+	}
+	for _, c := range cases {
+		groups := Split(newUrls(c.count))
+		if len(groups) != len(c.sizes) {
+			t.Errorf("Split(%d) = %d groups, want %d", c.count, len(groups), len(c.sizes))
+			continue
+		}
+		for i, group := range groups {
+			if len(group) != c.sizes[i] {
+				t.Errorf("Split(%d) group %d = %d urls, want %d", c.count, i, len(group), c.sizes[i])
+			}
+		}
+	}
+}
+
+func TestLastMod(t *testing.T) {
+	t1 := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	t2 := time.Date(2019, 6, 1, 0, 0, 0, 0, time.UTC)
+	cases := []struct {
+		urls []Url
+		want time.Time
+	}{
+		{nil, time.Time{}},
+		{[]Url{{LastMod: t1}}, t1},
+		{[]Url{{LastMod: t2}, {}, {LastMod: t1}}, t2},
+	}
+	for _, c := range cases {
+		if got := LastMod(c.urls); !got.Equal(c.want) {
+			t.Errorf("LastMod = %v, want %v", got, c.want)
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	content, err := Render([]Url{
+		{Loc: "https://example.com/a?x=1&y=2", LastMod: time.Date(2019, 6, 1, 8, 0, 0, 0, time.UTC), ChangeFreq: "weekly", Priority: 1.5},
+		{Loc: "https://example.com/b"},
+		{Loc: "https://example.com/%E5%BF%AB%E9%80%9F/%E5%BC%80%E5%A7%8B"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		text string
+		want bool
+	}{
+		{`<?xml version="1.0" encoding="UTF-8"?>`, true},
+		{`<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">`, true},
+		{`<loc>https://example.com/a?x=1&amp;y=2</loc>`, true},
+		{`<loc>https://example.com/%E5%BF%AB%E9%80%9F/%E5%BC%80%E5%A7%8B</loc>`, true},
+		{`<lastmod>2019-06-01T08:00:00Z</lastmod>`, true},
+		{`<priority>1.0</priority>`, true},
+		{`<priority>0`, false},
+		{`<lastmod></lastmod>`, false},
+	}
+	for _, c := range cases {
+		if got := strings.Contains(string(content), c.text); got != c.want {
+			t.Errorf("Render() contains %s = %v, want %v", c.text, got, c.want)
+		}
+	}
+}
+
+func TestRenderIndex(t *testing.T) {
+	content, err := RenderIndex([]Sitemap{{Loc: "https://example.com/sitemap-1.xml"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"<sitemapindex", "<loc>https://example.com/sitemap-1.xml</loc>"} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("RenderIndex() = %s, want containing %s", content, want)
+		}
+	}
+}
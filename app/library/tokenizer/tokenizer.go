@@ -0,0 +1,231 @@
+package lib_tokenizer
+
+import (
+	"strings"
+	"unicode"
+)
+
+// 分词器接口，检索索引及查询解析需要使用同一个分词器，以保证词项一致
+type Tokenizer interface {
+	// 将文本切分为词项列表，返回的词项顺序与其在文本中出现的顺序一致
+	Tokenize(text string) []string
+}
+
+// 支持返回词项位置的分词器，用于检索结果的匹配高亮
+type OffsetTokenizer interface {
+	Tokenizer
+	// 将文本切分为带有位置信息的词项列表
+	TokenizeOffsets(text string) []Token
+}
+
+// 支持扩展索引词项的分词器，索引时除分词结果外同时索引其包含的较短词项，
+// 使较短的查询词(如单字)可以匹配到文档中较长的词项
+type IndexTokenizer interface {
+	OffsetTokenizer
+	// 将文本切分为索引使用的词项列表，每个元素为同一位置上的词项，第一个为分词结果，其余为扩展词项
+	TokenizeIndex(text string) [][]Token
+}
+
+// 带有位置信息的词项，位置为字符(rune)偏移量，左闭右开
+type Token struct {
+	Text  string `json:"text"`
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+}
+
+// 标准分词器：
+// 1. 中文(CJK)文本使用词典进行正向最大匹配分词，词典无法匹配的连续文本使用二元(bigram)切分；
+// 2. 英文及数字按照单词切分并转换为小写，英文单词执行词干提取(Porter Stemmer)；
+type Standard struct {
+	dict *Dictionary
+	stem bool
+}
+
+// 默认分词器，使用内置词典
+var defaultTokenizer = New(DefaultDictionary())
+
+// 创建标准分词器，dict为nil时中文文本仅使用二元切分
+func New(dict *Dictionary) *Standard {
+	return &Standard{
+		dict: dict,
+		stem: true,
+	}
+}
+
+// 获得默认分词器
+func Default() *Standard {
+	return defaultTokenizer
+}
+
+// 设置是否对英文单词执行词干提取，默认开启
+func (t *Standard) SetStemEnabled(enabled bool) {
+	t.stem = enabled
+}
+
+// 将文本切分为词项列表
+func (t *Standard) Tokenize(text string) []string {
+	tokens := t.TokenizeOffsets(text)
+	result := make([]string, len(tokens))
+	for i, token := range tokens {
+		result[i] = token.Text
+	}
+	return result
+}
+
+// 将文本切分为带有位置信息的词项列表
+func (t *Standard) TokenizeOffsets(text string) []Token {
+	tokens := make([]Token, 0)
+	word   := make([]rune, 0)
+	cjk    := make([]rune, 0)
+	pos    := 0
+	flushWord := func() {
+		if len(word) > 0 {
+			tokens = append(tokens, Token{
+				Text:  t.normalize(string(word)),
+				Start: pos - len(word),
+				End:   pos,
+			})
+			word = word[:0]
+		}
+	}
+	flushCjk := func() {
+		if len(cjk) > 0 {
+			for _, token := range t.segment(cjk) {
+				token.Start += pos - len(cjk)
+				token.End   += pos - len(cjk)
+				tokens = append(tokens, token)
+			}
+			cjk = cjk[:0]
+		}
+	}
+	for _, r := range text {
+		switch {
+		case isCjk(r):
+			flushWord()
+			cjk = append(cjk, r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_':
+			flushCjk()
+			word = append(word, r)
+		default:
+			flushWord()
+			flushCjk()
+		}
+		pos++
+	}
+	flushWord()
+	flushCjk()
+	return tokens
+}
+
+// 将文本切分为索引使用的词项列表，每个位置上除分词结果外，对于中文词项同时包含其中的词典词语、
+// 二元词项及单字，例如"数据库"同时索引"数据"、"据库"、"数"、"据"、"库"。
+// 同一文本区间的词项只出现一次，扩展词项与其所属的分词结果位置相同，不影响短语查询
+func (t *Standard) TokenizeIndex(text string) [][]Token {
+	tokens  := t.TokenizeOffsets(text)
+	runes   := []rune(text)
+	result  := make([][]Token, len(tokens))
+	emitted := make(map[[2]int]struct{}, len(tokens))
+	for _, token := range tokens {
+		emitted[[2]int{token.Start, token.End}] = struct{}{}
+	}
+	for i, token := range tokens {
+		result[i] = []Token{token}
+		if token.End-token.Start < 2 || !isCjk(runes[token.Start]) {
+			continue
+		}
+		for start := token.Start; start < token.End; start++ {
+			for end := start + 1; end <= token.End; end++ {
+				span := [2]int{start, end}
+				if _, ok := emitted[span]; ok {
+					continue
+				}
+				word := string(runes[start:end])
+				if end-start > 2 && (t.dict == nil || !t.dict.Contains(word)) {
+					continue
+				}
+				emitted[span] = struct{}{}
+				result[i] = append(result[i], Token{
+					Text:  word,
+					Start: start,
+					End:   end,
+				})
+			}
+		}
+	}
+	return result
+}
+
+// 英文及数字单词标准化：转换为小写，纯英文字母单词执行词干提取
+func (t *Standard) normalize(word string) string {
+	word = strings.ToLower(word)
+	if t.stem && isAsciiLetters(word) {
+		return Stem(word)
+	}
+	return word
+}
+
+// 对连续的CJK文本进行分词，使用正向最大匹配，词典无法匹配的部分使用二元切分
+func (t *Standard) segment(text []rune) []Token {
+	tokens  := make([]Token, 0)
+	unknown := 0 // 词典无法匹配部分的起始位置
+	for i := 0; i < len(text); {
+		n := 0
+		if t.dict != nil {
+			n = t.dict.match(text[i:])
+		}
+		if n == 0 {
+			i++
+			continue
+		}
+		tokens  = append(tokens, bigram(text[unknown:i], unknown)...)
+		tokens  = append(tokens, Token{
+			Text:  string(text[i : i+n]),
+			Start: i,
+			End:   i + n,
+		})
+		i      += n
+		unknown = i
+	}
+	return append(tokens, bigram(text[unknown:], unknown)...)
+}
+
+// 二元切分，单个字符时返回该字符，offset为text在原文本中的起始位置
+func bigram(text []rune, offset int) []Token {
+	if len(text) == 0 {
+		return nil
+	}
+	if len(text) == 1 {
+		return []Token{{
+			Text:  string(text),
+			Start: offset,
+			End:   offset + 1,
+		}}
+	}
+	tokens := make([]Token, 0, len(text)-1)
+	for i := 0; i < len(text)-1; i++ {
+		tokens = append(tokens, Token{
+			Text:  string(text[i : i+2]),
+			Start: offset + i,
+			End:   offset + i + 2,
+		})
+	}
+	return tokens
+}
+
+// 判断是否为CJK字符
+func isCjk(r rune) bool {
+	return unicode.Is(unicode.Han, r) ||
+		unicode.Is(unicode.Hiragana, r) ||
+		unicode.Is(unicode.Katakana, r) ||
+		unicode.Is(unicode.Hangul, r)
+}
+
+// 判断字符串是否只包含ASCII英文字母
+func isAsciiLetters(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < 'a' || s[i] > 'z' {
+			return false
+		}
+	}
+	return len(s) > 0
+}
@@ -0,0 +1,100 @@
+package lib_tokenizer
+
+import (
+	"github.com/gogf/gf/g/os/gfile"
+	"strings"
+	"unicode/utf8"
+)
+
+// 分词词典，创建后只读，并发安全
+type Dictionary struct {
+	words  map[string]struct{}
+	maxLen int // 词典中最长词语的字符数
+}
+
+// 内置词典，包含文档中常用的中文词语
+var builtinWords = []string{
+	"框架", "模块", "组件", "功能", "特性", "介绍", "快速", "开始", "安装", "示例", "使用", "说明",
+	"文档", "手册", "教程", "开发", "项目", "工程", "目录", "结构", "设计", "架构", "性能", "测试", "基准",
+	"配置", "参数", "变量", "常量", "类型", "方法", "函数", "接口", "对象", "结构体",
+	"路由", "注册", "服务", "客户端", "请求", "响应", "输入", "输出", "中间件",
+	"控制器", "执行对象", "回调", "事件", "钩子", "分组", "域名", "端口", "静态", "文件", "上传", "下载",
+	"会话", "模板", "视图", "标签", "布局", "内置", "自定义",
+	"数据库", "数据", "模型", "查询", "事务", "操作", "连接", "主从", "集群", "读写分离",
+	"缓存", "内存", "并发", "安全", "协程", "锁", "队列", "数组", "链表", "集合", "容器", "池",
+	"日志", "错误", "异常", "调试", "监控", "热更新", "热重启", "平滑重启", "进程", "定时器",
+	"编码", "解码", "加密", "解密", "校验", "转换", "字符串", "正则",
+	"命令", "环境", "部署", "发布", "版本", "更新", "升级", "兼容", "依赖", "包管理",
+	"网络", "通信", "协议", "长连接", "短连接", "广播", "心跳", "超时", "重试", "负载均衡",
+	"时间", "日期", "格式化", "时区", "随机数", "工具", "开源", "社区", "贡献", "捐赠", "常见问题",
+}
+
+// 默认词典对象
+var defaultDictionary = NewDictionary(builtinWords...)
+
+// 获得内置的默认词典
+func DefaultDictionary() *Dictionary {
+	return defaultDictionary
+}
+
+// 根据给定词语列表创建词典
+func NewDictionary(words ...string) *Dictionary {
+	dict := &Dictionary{
+		words: make(map[string]struct{}, len(words)),
+	}
+	for _, word := range words {
+		dict.add(word)
+	}
+	return dict
+}
+
+// 加载词典文件(每行一个词语，#开头为注释)，并与内置词典合并
+func LoadDictionary(path string) *Dictionary {
+	words := append([]string{}, builtinWords...)
+	for _, line := range strings.Split(gfile.GetContents(path), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		// 兼容"词语 词频 词性"格式的词典文件
+		words = append(words, strings.Fields(line)[0])
+	}
+	return NewDictionary(words...)
+}
+
+// 词典中的词语数量
+func (d *Dictionary) Len() int {
+	return len(d.words)
+}
+
+// 判断词语是否存在于词典中
+func (d *Dictionary) Contains(word string) bool {
+	_, ok := d.words[word]
+	return ok
+}
+
+// 添加词语到词典，仅用于创建词典阶段
+func (d *Dictionary) add(word string) {
+	word = strings.TrimSpace(word)
+	if word == "" {
+		return
+	}
+	d.words[word] = struct{}{}
+	if n := utf8.RuneCountInString(word); n > d.maxLen {
+		d.maxLen = n
+	}
+}
+
+// 返回文本开头能够匹配的最长词语的字符数，无法匹配时返回0
+func (d *Dictionary) match(text []rune) int {
+	n := d.maxLen
+	if n > len(text) {
+		n = len(text)
+	}
+	for ; n > 0; n-- {
+		if _, ok := d.words[string(text[:n])]; ok {
+			return n
+		}
+	}
+	return 0
+}
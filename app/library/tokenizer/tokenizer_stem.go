@@ -0,0 +1,271 @@
+package lib_tokenizer
+
+// 英文单词词干提取(Porter Stemming Algorithm)，输入需为小写英文字母单词
+func Stem(word string) string {
+	if len(word) <= 2 {
+		return word
+	}
+	s := &stemmer{
+		b: []byte(word),
+		k: len(word) - 1,
+	}
+	s.step1ab()
+	if s.k > 0 {
+		s.step1c()
+		s.step2()
+		s.step3()
+		s.step4()
+		s.step5()
+	}
+	return string(s.b[:s.k+1])
+}
+
+// 词干提取状态，b[0:k+1]为当前单词，j为后缀匹配的分界位置
+type stemmer struct {
+	b []byte
+	k int
+	j int
+}
+
+// 判断b[i]是否为辅音
+func (s *stemmer) cons(i int) bool {
+	switch s.b[i] {
+	case 'a', 'e', 'i', 'o', 'u':
+		return false
+	case 'y':
+		if i == 0 {
+			return true
+		}
+		return !s.cons(i - 1)
+	}
+	return true
+}
+
+// 计算b[0:j+1]中辅音-元音序列的数量m，即[C](VC){m}[V]
+func (s *stemmer) m() int {
+	n, i := 0, 0
+	for {
+		if i > s.j {
+			return n
+		}
+		if !s.cons(i) {
+			break
+		}
+		i++
+	}
+	i++
+	for {
+		for {
+			if i > s.j {
+				return n
+			}
+			if s.cons(i) {
+				break
+			}
+			i++
+		}
+		i++
+		n++
+		for {
+			if i > s.j {
+				return n
+			}
+			if !s.cons(i) {
+				break
+			}
+			i++
+		}
+		i++
+	}
+}
+
+// 判断b[0:j+1]中是否包含元音
+func (s *stemmer) vowelInStem() bool {
+	for i := 0; i <= s.j; i++ {
+		if !s.cons(i) {
+			return true
+		}
+	}
+	return false
+}
+
+// 判断b[j-1:j+1]是否为相同的辅音
+func (s *stemmer) doubleC(j int) bool {
+	if j < 1 || s.b[j] != s.b[j-1] {
+		return false
+	}
+	return s.cons(j)
+}
+
+// 判断b[i-2:i+1]是否为辅音-元音-辅音结构，且最后的辅音不为w、x、y
+func (s *stemmer) cvc(i int) bool {
+	if i < 2 || !s.cons(i) || s.cons(i-1) || !s.cons(i-2) {
+		return false
+	}
+	switch s.b[i] {
+	case 'w', 'x', 'y':
+		return false
+	}
+	return true
+}
+
+// 判断单词是否以suffix结尾，是则设置j为后缀前的位置
+func (s *stemmer) ends(suffix string) bool {
+	l := len(suffix)
+	if l > s.k+1 || string(s.b[s.k-l+1:s.k+1]) != suffix {
+		return false
+	}
+	s.j = s.k - l
+	return true
+}
+
+// 将b[j+1:k+1]替换为value
+func (s *stemmer) setTo(value string) {
+	s.b = append(s.b[:s.j+1], value...)
+	s.k = s.j + len(value)
+}
+
+// m() > 0时将后缀替换为value
+func (s *stemmer) r(value string) {
+	if s.m() > 0 {
+		s.setTo(value)
+	}
+}
+
+// 处理复数及-ed、-ing后缀
+func (s *stemmer) step1ab() {
+	if s.b[s.k] == 's' {
+		if s.ends("sses") {
+			s.k -= 2
+		} else if s.ends("ies") {
+			s.setTo("i")
+		} else if s.b[s.k-1] != 's' {
+			s.k--
+		}
+	}
+	if s.ends("eed") {
+		if s.m() > 0 {
+			s.k--
+		}
+	} else if (s.ends("ed") || s.ends("ing")) && s.vowelInStem() {
+		s.k = s.j
+		if s.ends("at") {
+			s.setTo("ate")
+		} else if s.ends("bl") {
+			s.setTo("ble")
+		} else if s.ends("iz") {
+			s.setTo("ize")
+		} else if s.doubleC(s.k) {
+			s.k--
+			switch s.b[s.k] {
+			case 'l', 's', 'z':
+				s.k++
+			}
+		} else {
+			s.j = s.k
+			if s.m() == 1 && s.cvc(s.k) {
+				s.setTo("e")
+			}
+		}
+	}
+}
+
+// 词干中包含元音时，将结尾的y替换为i
+func (s *stemmer) step1c() {
+	if s.ends("y") && s.vowelInStem() {
+		s.b[s.k] = 'i'
+	}
+}
+
+// 双后缀替换为单后缀，例如: -ization => -ize
+func (s *stemmer) step2() {
+	if s.k < 1 {
+		return
+	}
+	for _, rule := range step2Rules[s.b[s.k-1]] {
+		if s.ends(rule[0]) {
+			s.r(rule[1])
+			return
+		}
+	}
+}
+
+// 处理-ic-、-full、-ness等后缀
+func (s *stemmer) step3() {
+	for _, rule := range step3Rules[s.b[s.k]] {
+		if s.ends(rule[0]) {
+			s.r(rule[1])
+			return
+		}
+	}
+}
+
+// m() > 1时去掉-ant、-ence等后缀
+func (s *stemmer) step4() {
+	if s.k < 1 {
+		return
+	}
+	matched := false
+	for _, suffix := range step4Rules[s.b[s.k-1]] {
+		if s.ends(suffix) {
+			if suffix == "ion" && (s.j < 0 || (s.b[s.j] != 's' && s.b[s.j] != 't')) {
+				continue
+			}
+			matched = true
+			break
+		}
+	}
+	if matched && s.m() > 1 {
+		s.k = s.j
+	}
+}
+
+// m() > 1时去掉结尾的-e，以及-ll结尾时去掉一个l
+func (s *stemmer) step5() {
+	s.j = s.k
+	if s.b[s.k] == 'e' {
+		a := s.m()
+		if a > 1 || a == 1 && !s.cvc(s.k-1) {
+			s.k--
+		}
+	}
+	if s.b[s.k] == 'l' && s.doubleC(s.k) && s.m() > 1 {
+		s.k--
+	}
+}
+
+// step2替换规则，按照后缀的倒数第二个字符索引
+var step2Rules = map[byte][][2]string{
+	'a': {{"ational", "ate"}, {"tional", "tion"}},
+	'c': {{"enci", "ence"}, {"anci", "ance"}},
+	'e': {{"izer", "ize"}},
+	'l': {{"bli", "ble"}, {"alli", "al"}, {"entli", "ent"}, {"eli", "e"}, {"ousli", "ous"}},
+	'o': {{"ization", "ize"}, {"ation", "ate"}, {"ator", "ate"}},
+	's': {{"alism", "al"}, {"iveness", "ive"}, {"fulness", "ful"}, {"ousness", "ous"}},
+	't': {{"aliti", "al"}, {"iviti", "ive"}, {"biliti", "ble"}},
+	'g': {{"logi", "log"}},
+}
+
+// step3替换规则，按照后缀的最后一个字符索引
+var step3Rules = map[byte][][2]string{
+	'e': {{"icate", "ic"}, {"ative", ""}, {"alize", "al"}},
+	'i': {{"iciti", "ic"}},
+	'l': {{"ical", "ic"}, {"ful", ""}},
+	's': {{"ness", ""}},
+}
+
+// step4删除的后缀，按照后缀的倒数第二个字符索引
+var step4Rules = map[byte][]string{
+	'a': {"al"},
+	'c': {"ance", "ence"},
+	'e': {"er"},
+	'i': {"ic"},
+	'l': {"able", "ible"},
+	'n': {"ant", "ement", "ment", "ent"},
+	'o': {"ion", "ou"},
+	's': {"ism"},
+	't': {"ate", "iti"},
+	'u': {"ous"},
+	'v': {"ive"},
+	'z': {"ize"},
+}
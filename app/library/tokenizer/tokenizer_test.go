@@ -0,0 +1,116 @@
+package lib_tokenizer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	cases := []struct {
+		text string
+		want []string
+	}{
+		{"", []string{}},
+		{"Hello World", []string{"hello", "world"}},
+		{"running tests", []string{"run", "test"}},
+		{"gf框架v1.5", []string{"gf", "框架", "v1", "5"}},
+		{"使用数据库", []string{"使用", "数据库"}},
+		{"你好吗", []string{"你好", "好吗"}},
+		{"好", []string{"好"}},
+		{"使用你好", []string{"使用", "你好"}},
+		{"go_lang, 123!", []string{"go_lang", "123"}},
+	}
+	for _, c := range cases {
+		if got := Default().Tokenize(c.text); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("Tokenize(%q) = %q, want %q", c.text, got, c.want)
+		}
+	}
+}
+
+func TestTokenizeWithoutStem(t *testing.T) {
+	tokenizer := New(DefaultDictionary())
+	tokenizer.SetStemEnabled(false)
+	want := []string{"running", "tests"}
+	if got := tokenizer.Tokenize("Running Tests"); !reflect.DeepEqual(got, want) {
+		t.Errorf("Tokenize = %q, want %q", got, want)
+	}
+}
+
+func TestTokenizeOffsets(t *testing.T) {
+	cases := []struct {
+		text string
+		want []Token
+	}{
+		{"go 数据库", []Token{{"go", 0, 2}, {"数据库", 3, 6}}},
+		{"你好吗", []Token{{"你好", 0, 2}, {"好吗", 1, 3}}},
+	}
+	for _, c := range cases {
+		if got := Default().TokenizeOffsets(c.text); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("TokenizeOffsets(%q) = %v, want %v", c.text, got, c.want)
+		}
+	}
+}
+
+func TestTokenizeIndex(t *testing.T) {
+	cases := []struct {
+		text string
+		want [][]string
+	}{
+		{"go", [][]string{{"go"}}},
+		{"数据库", [][]string{{"数据库", "数", "数据", "据", "据库", "库"}}},
+		{"你好吗", [][]string{{"你好", "你", "好"}, {"好吗", "吗"}}},
+		{"好", [][]string{{"好"}}},
+	}
+	for _, c := range cases {
+		groups := Default().TokenizeIndex(c.text)
+		got    := make([][]string, len(groups))
+		for i, group := range groups {
+			for _, token := range group {
+				got[i] = append(got[i], token.Text)
+			}
+		}
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("TokenizeIndex(%q) = %q, want %q", c.text, got, c.want)
+		}
+	}
+}
+
+func TestDictionary(t *testing.T) {
+	dict := NewDictionary("数据", "数据库", " ")
+	if dict.Len() != 2 {
+		t.Errorf("Len = %d, want 2", dict.Len())
+	}
+	cases := []struct {
+		text string
+		want int
+	}{
+		{"数据库连接", 3},
+		{"数据连接", 2},
+		{"连接", 0},
+		{"", 0},
+	}
+	for _, c := range cases {
+		if got := dict.match([]rune(c.text)); got != c.want {
+			t.Errorf("match(%q) = %d, want %d", c.text, got, c.want)
+		}
+	}
+}
+
+func TestStem(t *testing.T) {
+	cases := []struct {
+		word string
+		want string
+	}{
+		{"caresses", "caress"},
+		{"ponies", "poni"},
+		{"running", "run"},
+		{"connection", "connect"},
+		{"relational", "relat"},
+		{"go", "go"},
+	}
+	for _, c := range cases {
+		if got := Stem(c.word); got != c.want {
+			t.Errorf("Stem(%q) = %q, want %q", c.word, got, c.want)
+		}
+	}
+}
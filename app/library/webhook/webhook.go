@@ -0,0 +1,153 @@
+package lib_webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/gogf/gf/g/encoding/gjson"
+	"github.com/gogf/gf/g/util/gconv"
+	"hash"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	PROVIDER_GITHUB = "github"
+	PROVIDER_GITEE  = "gitee"
+	PROVIDER_GITLAB = "gitlab"
+	// Gitee签名模式允许的时间戳误差，超出范围的请求视为重放
+	giteeTimestampWindow = 5 * time.Minute
+)
+
+var (
+	// 无法识别的webhook请求来源
+	ErrUnknownProvider = errors.New("unknown webhook provider")
+	// 签名或者令牌校验失败
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	// 未配置webhook密钥
+	ErrEmptySecret = errors.New("webhook secret not configured")
+)
+
+// 代码仓库推送事件
+type Push struct {
+	Provider   string `json:"provider"`   // 来源平台: github/gitee/gitlab
+	Event      string `json:"event"`      // 原始事件名称
+	Ref        string `json:"ref"`        // 推送的引用，例如: refs/heads/master
+	Branch     string `json:"branch"`     // 推送的分支名称，非分支推送时为空
+	Before     string `json:"before"`     // 推送前的提交
+	After      string `json:"after"`      // 推送后的提交
+	Repository string `json:"repository"` // 仓库全称
+	Pusher     string `json:"pusher"`     // 推送者
+}
+
+// 判断是否为推送事件(ping等事件只用于连通性测试)
+func (p *Push) IsPush() bool {
+	switch p.Provider {
+	case PROVIDER_GITHUB:
+		return p.Event == "push"
+	default:
+		return p.Event == "Push Hook"
+	}
+}
+
+// 校验并解析webhook请求，支持GitHub、Gitee及GitLab的推送事件
+func Parse(header http.Header, body []byte, secret string) (*Push, error) {
+	if secret == "" {
+		return nil, ErrEmptySecret
+	}
+	push := &Push{}
+	switch {
+	case header.Get("X-GitHub-Event") != "":
+		push.Provider = PROVIDER_GITHUB
+		push.Event    = header.Get("X-GitHub-Event")
+		if !verifyGithub(header, body, secret) {
+			return nil, ErrInvalidSignature
+		}
+	case header.Get("X-Gitee-Event") != "":
+		push.Provider = PROVIDER_GITEE
+		push.Event    = header.Get("X-Gitee-Event")
+		if !verifyGitee(header, secret) {
+			return nil, ErrInvalidSignature
+		}
+	case header.Get("X-Gitlab-Event") != "":
+		push.Provider = PROVIDER_GITLAB
+		push.Event    = header.Get("X-Gitlab-Event")
+		if !equal(header.Get("X-Gitlab-Token"), secret) {
+			return nil, ErrInvalidSignature
+		}
+	default:
+		return nil, ErrUnknownProvider
+	}
+	if !push.IsPush() {
+		return push, nil
+	}
+	j, err := gjson.DecodeToJson(body)
+	if err != nil {
+		return nil, fmt.Errorf("invalid webhook payload: %v", err)
+	}
+	push.Ref    = j.GetString("ref")
+	push.Before = j.GetString("before")
+	push.After  = j.GetString("after")
+	if strings.HasPrefix(push.Ref, "refs/heads/") {
+		push.Branch = strings.TrimPrefix(push.Ref, "refs/heads/")
+	}
+	if push.Provider == PROVIDER_GITLAB {
+		push.Repository = j.GetString("project.path_with_namespace")
+		push.Pusher     = j.GetString("user_username")
+	} else {
+		push.Repository = j.GetString("repository.full_name")
+		push.Pusher     = j.GetString("pusher.name")
+	}
+	return push, nil
+}
+
+// GitHub签名校验，优先使用X-Hub-Signature-256(HMAC-SHA256)，兼容X-Hub-Signature(HMAC-SHA1)
+func verifyGithub(header http.Header, body []byte, secret string) bool {
+	if signature := header.Get("X-Hub-Signature-256"); signature != "" {
+		return equal(signature, "sha256="+hmacHex(sha256.New, body, secret))
+	}
+	if signature := header.Get("X-Hub-Signature"); signature != "" {
+		return equal(signature, "sha1="+hmacHex(sha1.New, body, secret))
+	}
+	return false
+}
+
+// Gitee校验，X-Gitee-Token为密码明文(密码模式)，
+// 或者为Base64(HmacSHA256(timestamp + "\n" + secret))签名(签名模式)，签名模式的时间戳(毫秒)需要在允许的误差范围内
+func verifyGitee(header http.Header, secret string) bool {
+	token := header.Get("X-Gitee-Token")
+	if token == "" {
+		return false
+	}
+	if equal(token, secret) {
+		return true
+	}
+	timestamp := header.Get("X-Gitee-Timestamp")
+	if timestamp == "" {
+		return false
+	}
+	diff := time.Since(time.Unix(0, gconv.Int64(timestamp)*int64(time.Millisecond)))
+	if diff > giteeTimestampWindow || diff < -giteeTimestampWindow {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "\n" + secret))
+	return equal(token, base64.StdEncoding.EncodeToString(mac.Sum(nil)))
+}
+
+// 计算HMAC并返回十六进制字符串
+func hmacHex(h func() hash.Hash, body []byte, secret string) string {
+	mac := hmac.New(h, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// 常量时间的字符串比较，防止时序攻击
+func equal(a, b string) bool {
+	return hmac.Equal([]byte(a), []byte(b))
+}
@@ -0,0 +1,104 @@
+package lib_webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+const (
+	testSecret  = "s3cret"
+	testPayload = `{"ref":"refs/heads/master","before":"a1","after":"b2","repository":{"full_name":"gogf/docfile"},"pusher":{"name":"john"},"project":{"path_with_namespace":"gogf/docfile"},"user_username":"jane"}`
+)
+
+// 创建请求头
+func newHeader(kv ...string) http.Header {
+	header := http.Header{}
+	for i := 0; i+1 < len(kv); i += 2 {
+		header.Set(kv[i], kv[i+1])
+	}
+	return header
+}
+
+// Gitee签名模式的令牌
+func giteeSign(timestamp string, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "\n" + secret))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestParseSignature(t *testing.T) {
+	body  := []byte(testPayload)
+	now   := fmt.Sprint(time.Now().UnixNano() / int64(time.Millisecond))
+	stale := fmt.Sprint(time.Now().Add(-10*time.Minute).UnixNano() / int64(time.Millisecond))
+	cases := []struct {
+		name   string
+		header http.Header
+		secret string
+		err    error
+	}{
+		{"empty secret", newHeader("X-GitHub-Event", "push"), "", ErrEmptySecret},
+		{"unknown provider", newHeader("X-Other-Event", "push"), testSecret, ErrUnknownProvider},
+		{"github sha256", newHeader("X-GitHub-Event", "push", "X-Hub-Signature-256", "sha256="+hmacHex(sha256.New, body, testSecret)), testSecret, nil},
+		{"github sha1", newHeader("X-GitHub-Event", "push", "X-Hub-Signature", "sha1="+hmacHex(sha1.New, body, testSecret)), testSecret, nil},
+		{"github sha1 wrong", newHeader("X-GitHub-Event", "push", "X-Hub-Signature", "sha1=d2c3b6a5b1c0f6d4bdeb7e6c7ee5f8f9e1e1c1d1"), testSecret, ErrInvalidSignature},
+		{"github wrong secret", newHeader("X-GitHub-Event", "push", "X-Hub-Signature-256", "sha256="+hmacHex(sha256.New, body, "other")), testSecret, ErrInvalidSignature},
+		{"github missing signature", newHeader("X-GitHub-Event", "push"), testSecret, ErrInvalidSignature},
+		{"gitee password", newHeader("X-Gitee-Event", "Push Hook", "X-Gitee-Token", testSecret), testSecret, nil},
+		{"gitee sign", newHeader("X-Gitee-Event", "Push Hook", "X-Gitee-Token", giteeSign(now, testSecret), "X-Gitee-Timestamp", now), testSecret, nil},
+		{"gitee sign wrong timestamp", newHeader("X-Gitee-Event", "Push Hook", "X-Gitee-Token", giteeSign(now, testSecret), "X-Gitee-Timestamp", now+"1"), testSecret, ErrInvalidSignature},
+		{"gitee sign stale timestamp", newHeader("X-Gitee-Event", "Push Hook", "X-Gitee-Token", giteeSign(stale, testSecret), "X-Gitee-Timestamp", stale), testSecret, ErrInvalidSignature},
+		{"gitee sign invalid timestamp", newHeader("X-Gitee-Event", "Push Hook", "X-Gitee-Token", giteeSign("abc", testSecret), "X-Gitee-Timestamp", "abc"), testSecret, ErrInvalidSignature},
+		{"gitee empty token", newHeader("X-Gitee-Event", "Push Hook"), testSecret, ErrInvalidSignature},
+		{"gitlab token", newHeader("X-Gitlab-Event", "Push Hook", "X-Gitlab-Token", testSecret), testSecret, nil},
+		{"gitlab wrong token", newHeader("X-Gitlab-Event", "Push Hook", "X-Gitlab-Token", "wrong"), testSecret, ErrInvalidSignature},
+	}
+	for _, c := range cases {
+		if _, err := Parse(c.header, body, c.secret); err != c.err {
+			t.Errorf("%s: err = %v, want %v", c.name, err, c.err)
+		}
+	}
+}
+
+func TestParsePush(t *testing.T) {
+	body := []byte(testPayload)
+	cases := []struct {
+		header http.Header
+		want   Push
+	}{
+		{
+			newHeader("X-GitHub-Event", "push", "X-Hub-Signature-256", "sha256="+hmacHex(sha256.New, body, testSecret)),
+			Push{PROVIDER_GITHUB, "push", "refs/heads/master", "master", "a1", "b2", "gogf/docfile", "john"},
+		},
+		{
+			newHeader("X-Gitlab-Event", "Push Hook", "X-Gitlab-Token", testSecret),
+			Push{PROVIDER_GITLAB, "Push Hook", "refs/heads/master", "master", "a1", "b2", "gogf/docfile", "jane"},
+		},
+		{
+			newHeader("X-GitHub-Event", "ping", "X-Hub-Signature-256", "sha256="+hmacHex(sha256.New, body, testSecret)),
+			Push{Provider: PROVIDER_GITHUB, Event: "ping"},
+		},
+	}
+	for _, c := range cases {
+		push, err := Parse(c.header, body, testSecret)
+		if err != nil {
+			t.Errorf("Parse(%s) error: %v", c.want.Event, err)
+			continue
+		}
+		if *push != c.want {
+			t.Errorf("Parse = %+v, want %+v", *push, c.want)
+		}
+		if push.IsPush() != (c.want.Ref != "") {
+			t.Errorf("IsPush(%s) = %v", push.Event, push.IsPush())
+		}
+	}
+	header := newHeader("X-Gitlab-Event", "Push Hook", "X-Gitlab-Token", testSecret)
+	if _, err := Parse(header, []byte("not json"), testSecret); err == nil {
+		t.Errorf("Parse invalid payload: want error")
+	}
+}
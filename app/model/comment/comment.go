@@ -0,0 +1,313 @@
+package model_comment
+
+import (
+	"errors"
+	"gf-blog/app/library/document"
+	"gf-blog/app/model"
+	"github.com/gogf/gf/g"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// 数据表名称
+	TABLE = "comment"
+
+	// 评论状态
+	STATUS_PENDING  = "pending"  // 待审核
+	STATUS_APPROVED = "approved" // 已通过，对外显示
+	STATUS_SPAM     = "spam"     // 垃圾评论
+	STATUS_DELETED  = "deleted"  // 已删除
+
+	// 作者名称最大长度
+	maxAuthorLength = 64
+	// 评论内容默认最大长度(字符数)
+	defaultMaxLength = 5000
+)
+
+var (
+	// 评论参数错误
+	ErrEmptyAuthor   = errors.New("author is required")
+	ErrEmptyContent  = errors.New("content is required")
+	ErrTooLong       = errors.New("content or author is too long")
+	ErrInvalidParent = errors.New("invalid parent comment")
+	// 评论不存在
+	ErrNotFound = errors.New("comment not found")
+	// 不允许的状态变更
+	ErrInvalidTransition = errors.New("invalid status transition")
+
+	// 评论审核状态机: 当前状态 => 允许变更的目标状态，已删除为终止状态
+	transitions = map[string][]string{
+		STATUS_PENDING:  {STATUS_APPROVED, STATUS_SPAM, STATUS_DELETED},
+		STATUS_APPROVED: {STATUS_SPAM, STATUS_DELETED},
+		STATUS_SPAM:     {STATUS_APPROVED, STATUS_DELETED},
+		STATUS_DELETED:  {},
+	}
+)
+
+// 评论
+type Comment struct {
+	Id        int        `gconv:"id"         json:"id"`
+	Path      string     `gconv:"path"       json:"path"`            // 评论所属页面的路径，例如: /blog/hello-world、/net/ghttp
+	ParentId  int        `gconv:"parent_id"  json:"parentId"`        // 回复的评论ID，顶级评论为0
+	Author    string     `gconv:"author"     json:"author"`          // 评论者名称
+	Email     string     `gconv:"email"      json:"email,omitempty"` // 评论者邮箱，不对外显示
+	Markdown  string     `gconv:"markdown"   json:"markdown"`        // markdown内容
+	Html      string     `gconv:"html"       json:"html"`            // 使用安全子集渲染的html内容
+	Status    string     `gconv:"status"     json:"status"`          // 评论状态
+	Ip        string     `gconv:"ip"         json:"ip,omitempty"`    // 评论者IP，不对外显示
+	CreatedAt int64      `gconv:"created_at" json:"createdAt"`       // 创建时间(时间戳)
+	UpdatedAt int64      `gconv:"updated_at" json:"updatedAt"`       // 更新时间(时间戳)
+	Replies   []*Comment `gconv:"-"          json:"replies"`         // 回复列表
+}
+
+func init() {
+	model.Register(TABLE, map[string]string{
+		model.DB_SQLITE: `
+CREATE TABLE IF NOT EXISTS comment (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    path       VARCHAR(255) NOT NULL,
+    parent_id  INTEGER      NOT NULL DEFAULT 0,
+    author     VARCHAR(64)  NOT NULL DEFAULT '',
+    email      VARCHAR(128) NOT NULL DEFAULT '',
+    markdown   TEXT         NOT NULL,
+    html       TEXT         NOT NULL,
+    status     VARCHAR(16)  NOT NULL DEFAULT 'pending',
+    ip         VARCHAR(64)  NOT NULL DEFAULT '',
+    created_at INTEGER      NOT NULL DEFAULT 0,
+    updated_at INTEGER      NOT NULL DEFAULT 0
+);
+CREATE INDEX IF NOT EXISTS idx_comment_path_status ON comment (path, status);
+CREATE INDEX IF NOT EXISTS idx_comment_status ON comment (status, created_at)`,
+		model.DB_MYSQL: `
+CREATE TABLE IF NOT EXISTS comment (
+    id         INT UNSIGNED NOT NULL AUTO_INCREMENT,
+    path       VARCHAR(255) NOT NULL,
+    parent_id  INT UNSIGNED NOT NULL DEFAULT 0,
+    author     VARCHAR(64)  NOT NULL DEFAULT '',
+    email      VARCHAR(128) NOT NULL DEFAULT '',
+    markdown   TEXT         NOT NULL,
+    html       TEXT         NOT NULL,
+    status     VARCHAR(16)  NOT NULL DEFAULT 'pending',
+    ip         VARCHAR(64)  NOT NULL DEFAULT '',
+    created_at INT          NOT NULL DEFAULT 0,
+    updated_at INT          NOT NULL DEFAULT 0,
+    PRIMARY KEY (id),
+    KEY idx_path_status (path, status),
+    KEY idx_status (status, created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4`,
+	})
+}
+
+// 判断评论状态是否允许变更为目标状态
+func CanTransition(from string, to string) bool {
+	for _, status := range transitions[from] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
+
+// 创建评论，根据comment.moderation配置决定新评论是否需要审核，返回新评论的ID
+func Create(c *Comment) (int, error) {
+	c.Path     = "/" + strings.Trim(strings.TrimSpace(c.Path), "/")
+	c.Author   = strings.TrimSpace(c.Author)
+	c.Email    = strings.TrimSpace(c.Email)
+	c.Markdown = strings.TrimSpace(c.Markdown)
+	if c.Author == "" {
+		return 0, ErrEmptyAuthor
+	}
+	if c.Markdown == "" {
+		return 0, ErrEmptyContent
+	}
+	maxLength := g.Config().GetInt("comment.maxLength")
+	if maxLength <= 0 {
+		maxLength = defaultMaxLength
+	}
+	if utf8.RuneCountInString(c.Markdown) > maxLength || utf8.RuneCountInString(c.Author) > maxAuthorLength {
+		return 0, ErrTooLong
+	}
+	// 只能回复同一页面下已通过审核的评论
+	if c.ParentId > 0 {
+		parent, err := GetById(c.ParentId)
+		if err != nil {
+			return 0, err
+		}
+		if parent == nil || parent.Path != c.Path || parent.Status != STATUS_APPROVED {
+			return 0, ErrInvalidParent
+		}
+	}
+	c.Status = STATUS_PENDING
+	if !g.Config().GetBool("comment.moderation") {
+		c.Status = STATUS_APPROVED
+	}
+	c.Html      = lib_document.ParseSafeMarkdown(c.Markdown)
+	c.CreatedAt = time.Now().Unix()
+	c.UpdatedAt = c.CreatedAt
+	table, err := model.Table(TABLE)
+	if err != nil {
+		return 0, err
+	}
+	result, err := table.Data(g.Map{
+		"path":       c.Path,
+		"parent_id":  c.ParentId,
+		"author":     c.Author,
+		"email":      c.Email,
+		"markdown":   c.Markdown,
+		"html":       c.Html,
+		"status":     c.Status,
+		"ip":         c.Ip,
+		"created_at": c.CreatedAt,
+		"updated_at": c.UpdatedAt,
+	}).Insert()
+	if err != nil {
+		return 0, err
+	}
+	id, err := result.LastInsertId()
+	c.Id = int(id)
+	return c.Id, err
+}
+
+// 根据ID获得评论，不存在时返回nil
+func GetById(id int) (*Comment, error) {
+	table, err := model.Table(TABLE)
+	if err != nil {
+		return nil, err
+	}
+	record, err := table.Where("id=?", id).One()
+	if err != nil || record == nil {
+		return nil, err
+	}
+	c := new(Comment)
+	return c, record.ToStruct(c)
+}
+
+// 获得页面已通过审核的评论树，顶级评论按照时间从旧到新排序，不包含评论者的邮箱及IP。
+// 被回复的评论不可见(未通过审核或者已删除)时，该回复作为顶级评论显示
+func GetThread(path string) ([]*Comment, error) {
+	path = "/" + strings.Trim(path, "/")
+	table, err := model.Table(TABLE)
+	if err != nil {
+		return nil, err
+	}
+	result, err := table.Fields("id,path,parent_id,author,html,status,created_at,updated_at").
+		Where("path=? AND status=?", path, STATUS_APPROVED).OrderBy("created_at ASC, id ASC").Select()
+	if err != nil {
+		return nil, err
+	}
+	list := make([]*Comment, 0)
+	if err := result.ToStructs(&list); err != nil {
+		return nil, err
+	}
+	index := make(map[int]*Comment, len(list))
+	for _, c := range list {
+		c.Replies = make([]*Comment, 0)
+		index[c.Id] = c
+	}
+	roots := make([]*Comment, 0)
+	for _, c := range list {
+		if parent, ok := index[c.ParentId]; ok && c.ParentId > 0 {
+			parent.Replies = append(parent.Replies, c)
+		} else {
+			roots = append(roots, c)
+		}
+	}
+	return roots, nil
+}
+
+// 获得页面已通过审核的评论数量
+func GetCount(path string) (int, error) {
+	counts, err := GetCounts([]string{path})
+	if err != nil {
+		return 0, err
+	}
+	return counts["/"+strings.Trim(path, "/")], nil
+}
+
+// 批量获得多个页面已通过审核的评论数量，键名为规范化的页面路径(包含前导"/")
+func GetCounts(paths []string) (map[string]int, error) {
+	counts := make(map[string]int, len(paths))
+	if len(paths) == 0 {
+		return counts, nil
+	}
+	args := make(g.Slice, 0, len(paths))
+	for _, path := range paths {
+		path = "/" + strings.Trim(path, "/")
+		if _, ok := counts[path]; !ok {
+			counts[path] = 0
+			args = append(args, path)
+		}
+	}
+	// 页面数量较多时分批查询，避免超出数据库的参数数量限制
+	for _, batch := range model.Batches(args) {
+		table, err := model.Table(TABLE)
+		if err != nil {
+			return nil, err
+		}
+		result, err := table.Fields("path,COUNT(1) AS total").
+			Where("status=? AND path IN("+model.Placeholders(len(batch))+")", append(g.Slice{STATUS_APPROVED}, batch...)...).
+			GroupBy("path").Select()
+		if err != nil {
+			return nil, err
+		}
+		for _, record := range result {
+			counts[record["path"].String()] = record["total"].Int()
+		}
+	}
+	return counts, nil
+}
+
+// 分页获得指定状态的评论列表(管理使用，包含邮箱及IP)，status为空时返回所有状态，按照时间从新到旧排序
+func GetList(status string, page int, size int) ([]*Comment, int, error) {
+	where := "1=1"
+	args  := g.Slice{}
+	if status != "" {
+		where = "status=?"
+		args  = append(args, status)
+	}
+	table, err := model.Table(TABLE)
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := table.Where(where, args...).Count()
+	if err != nil {
+		return nil, 0, err
+	}
+	if page < 1 {
+		page = 1
+	}
+	table, _ = model.Table(TABLE)
+	result, err := table.Where(where, args...).OrderBy("created_at DESC, id DESC").ForPage(page, size).Select()
+	if err != nil {
+		return nil, 0, err
+	}
+	list := make([]*Comment, 0)
+	if err := result.ToStructs(&list); err != nil {
+		return nil, 0, err
+	}
+	return list, total, nil
+}
+
+// 变更评论状态，只允许状态机中定义的变更
+func Moderate(id int, status string) (*Comment, error) {
+	c, err := GetById(id)
+	if err != nil {
+		return nil, err
+	}
+	if c == nil {
+		return nil, ErrNotFound
+	}
+	if !CanTransition(c.Status, status) {
+		return nil, ErrInvalidTransition
+	}
+	table, err := model.Table(TABLE)
+	if err != nil {
+		return nil, err
+	}
+	c.Status    = status
+	c.UpdatedAt = time.Now().Unix()
+	_, err = table.Data(g.Map{"status": c.Status, "updated_at": c.UpdatedAt}).Where("id=?", id).Update()
+	return c, err
+}
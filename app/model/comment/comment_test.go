@@ -0,0 +1,35 @@
+package model_comment
+
+import "testing"
+
+func TestCanTransition(t *testing.T) {
+	statuses := []string{STATUS_PENDING, STATUS_APPROVED, STATUS_SPAM, STATUS_DELETED}
+	// 当前状态 => 允许变更的目标状态
+	allowed := map[string]map[string]bool{
+		STATUS_PENDING:  {STATUS_APPROVED: true, STATUS_SPAM: true, STATUS_DELETED: true},
+		STATUS_APPROVED: {STATUS_SPAM: true, STATUS_DELETED: true},
+		STATUS_SPAM:     {STATUS_APPROVED: true, STATUS_DELETED: true},
+		STATUS_DELETED:  {},
+	}
+	for _, from := range statuses {
+		for _, to := range statuses {
+			if got := CanTransition(from, to); got != allowed[from][to] {
+				t.Errorf("CanTransition(%s, %s) = %v, want %v", from, to, got, allowed[from][to])
+			}
+		}
+	}
+	cases := []struct {
+		from string
+		to   string
+	}{
+		{"", STATUS_APPROVED},
+		{"unknown", STATUS_APPROVED},
+		{STATUS_PENDING, ""},
+		{STATUS_PENDING, "unknown"},
+	}
+	for _, c := range cases {
+		if CanTransition(c.from, c.to) {
+			t.Errorf("CanTransition(%q, %q) = true, want false", c.from, c.to)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package model
+
+import (
+	"errors"
+	"github.com/gogf/gf/g"
+	"github.com/gogf/gf/g/database/gdb"
+	"github.com/gogf/gf/g/os/glog"
+	"strings"
+	"sync"
+	// SQLite驱动，MySQL驱动已由gdb内置
+	_ "github.com/mattn/go-sqlite3"
+)
+
+const (
+	// 支持的数据库类型
+	DB_SQLITE = "sqlite"
+	DB_MYSQL  = "mysql"
+
+	// IN条件单次查询的最大参数数量，SQLite默认限制单条语句最多999个参数
+	IN_BATCH_SIZE = 500
+)
+
+var (
+	// 未配置数据库或者数据库连接失败
+	ErrNoDatabase = errors.New("database is not available")
+	// 注册的数据表: 表名 => 数据库类型 => 建表语句
+	tables = make(map[string]map[string]string)
+	// 注册顺序，按照顺序建表(存在依赖关系的表需要后注册)
+	tableNames = make([]string, 0)
+	// 数据表只在第一次访问数据库时创建
+	migrateOnce = sync.Once{}
+)
+
+// 注册数据表的建表语句(需要使用CREATE TABLE IF NOT EXISTS)，按照数据库类型区分，在模型包的init中调用
+func Register(table string, schema map[string]string) {
+	if _, ok := tables[table]; !ok {
+		tableNames = append(tableNames, table)
+	}
+	tables[table] = schema
+}
+
+// 判断是否配置了数据库
+func Enabled() bool {
+	return g.Config().Get("database") != nil
+}
+
+// 获得默认的数据库对象，第一次访问时自动创建注册的数据表
+func DB() gdb.DB {
+	migrateOnce.Do(func() {
+		if err := Migrate(); err != nil {
+			glog.Error("database migrate failed:", err)
+		}
+	})
+	return g.DB()
+}
+
+// 获得数据表的链式操作对象，数据库不可用时返回ErrNoDatabase
+func Table(table string) (*gdb.Model, error) {
+	if !Enabled() {
+		return nil, ErrNoDatabase
+	}
+	db := DB()
+	if db == nil {
+		return nil, ErrNoDatabase
+	}
+	return db.Table(table), nil
+}
+
+// 获得默认数据库配置的数据库类型，默认为mysql
+func GetType() string {
+	if t := g.Config().GetString("database.default.0.type"); t != "" {
+		return strings.ToLower(t)
+	}
+	return DB_MYSQL
+}
+
+// 按照注册顺序创建所有数据表，已存在的数据表不做处理
+func Migrate() error {
+	db := g.DB()
+	if db == nil {
+		return nil
+	}
+	dbType := GetType()
+	for _, table := range tableNames {
+		for _, query := range strings.Split(tables[table][dbType], ";") {
+			if strings.TrimSpace(query) == "" {
+				continue
+			}
+			if _, err := db.Exec(query); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// 将参数列表按照IN_BATCH_SIZE分批，用于参数数量不确定的IN条件查询
+func Batches(args g.Slice) []g.Slice {
+	batches := make([]g.Slice, 0, len(args)/IN_BATCH_SIZE+1)
+	for len(args) > IN_BATCH_SIZE {
+		batches = append(batches, args[:IN_BATCH_SIZE])
+		args    = args[IN_BATCH_SIZE:]
+	}
+	if len(args) > 0 {
+		batches = append(batches, args)
+	}
+	return batches
+}
+
+// 生成IN条件的参数占位符，例如: ?,?,?
+func Placeholders(n int) string {
+	return strings.TrimRight(strings.Repeat("?,", n), ",")
+}
@@ -0,0 +1,260 @@
+package model_post
+
+import (
+	"errors"
+	"gf-blog/app/library/document"
+	"gf-blog/app/model"
+	"github.com/gogf/gf/g"
+	"github.com/gogf/gf/g/text/gregex"
+	"strings"
+	"time"
+)
+
+const (
+	// 数据表名称
+	TABLE = "post"
+
+	// 文章状态
+	STATUS_DRAFT     = "draft"     // 草稿，不对外显示
+	STATUS_PUBLISHED = "published" // 已发布，到达发布时间后对外显示
+
+	// 文章slug格式，只允许小写字母、数字及中划线
+	slugPattern = `^[a-z0-9]+(-[a-z0-9]+)*$`
+)
+
+var (
+	// 文章slug格式错误
+	ErrInvalidSlug = errors.New("invalid slug, only lowercase letters, digits and hyphens are allowed")
+	// 文章slug已被其他文章使用
+	ErrSlugExists = errors.New("slug already exists")
+	// 文章状态错误
+	ErrInvalidStatus = errors.New("invalid status")
+)
+
+// 博客文章
+type Post struct {
+	Id        int    `gconv:"id"         json:"id"`
+	Slug      string `gconv:"slug"       json:"slug"`      // 文章URL标识，例如: hello-world
+	Title     string `gconv:"title"      json:"title"`     // 标题
+	Markdown  string `gconv:"markdown"   json:"markdown"`  // markdown内容
+	Html      string `gconv:"html"       json:"html"`      // 保存时通过ParsePostMarkdown渲染的安全html内容
+	Status    string `gconv:"status"     json:"status"`    // 文章状态
+	Author    string `gconv:"author"     json:"author"`    // 作者
+	PublishAt int64  `gconv:"publish_at" json:"publishAt"` // 发布时间(时间戳)
+	CreatedAt int64  `gconv:"created_at" json:"createdAt"` // 创建时间(时间戳)
+	UpdatedAt int64  `gconv:"updated_at" json:"updatedAt"` // 更新时间(时间戳)
+	// 标签及分类保存在post_term表中
+	Tags       []string `gconv:"-" json:"tags"`       // 标签列表
+	Categories []string `gconv:"-" json:"categories"` // 分类列表，层级分类使用"/"分隔
+}
+
+func init() {
+	model.Register(TABLE, map[string]string{
+		model.DB_SQLITE: `
+CREATE TABLE IF NOT EXISTS post (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    slug       VARCHAR(128) NOT NULL UNIQUE,
+    title      VARCHAR(255) NOT NULL DEFAULT '',
+    markdown   TEXT         NOT NULL,
+    html       TEXT         NOT NULL,
+    status     VARCHAR(16)  NOT NULL DEFAULT 'draft',
+    author     VARCHAR(64)  NOT NULL DEFAULT '',
+    publish_at INTEGER      NOT NULL DEFAULT 0,
+    created_at INTEGER      NOT NULL DEFAULT 0,
+    updated_at INTEGER      NOT NULL DEFAULT 0
+);
+CREATE INDEX IF NOT EXISTS idx_post_status_publish ON post (status, publish_at)`,
+		model.DB_MYSQL: `
+CREATE TABLE IF NOT EXISTS post (
+    id         INT UNSIGNED NOT NULL AUTO_INCREMENT,
+    slug       VARCHAR(128) NOT NULL,
+    title      VARCHAR(255) NOT NULL DEFAULT '',
+    markdown   MEDIUMTEXT   NOT NULL,
+    html       MEDIUMTEXT   NOT NULL,
+    status     VARCHAR(16)  NOT NULL DEFAULT 'draft',
+    author     VARCHAR(64)  NOT NULL DEFAULT '',
+    publish_at INT          NOT NULL DEFAULT 0,
+    created_at INT          NOT NULL DEFAULT 0,
+    updated_at INT          NOT NULL DEFAULT 0,
+    PRIMARY KEY (id),
+    UNIQUE KEY uk_slug (slug),
+    KEY idx_status_publish (status, publish_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4`,
+	})
+}
+
+// 判断文章是否对外可见(已发布且到达发布时间)
+func (p *Post) IsVisible() bool {
+	return p.Status == STATUS_PUBLISHED && p.PublishAt <= time.Now().Unix()
+}
+
+// 文章的访问地址
+func (p *Post) Url() string {
+	return "/blog/" + p.Slug
+}
+
+// 根据slug获得文章，不存在时返回nil
+func GetBySlug(slug string) (*Post, error) {
+	return getOne("slug=?", slug)
+}
+
+// 根据ID获得文章，不存在时返回nil
+func GetById(id int) (*Post, error) {
+	return getOne("id=?", id)
+}
+
+// 分页获得对外可见的文章列表，按照发布时间从新到旧排序，page从1开始，同时返回文章总数
+func GetPublishedList(page int, size int) ([]*Post, int, error) {
+	return getList("status=? AND publish_at<=?", g.Slice{STATUS_PUBLISHED, time.Now().Unix()}, "publish_at DESC, id DESC", page, size)
+}
+
+// 分页获得作者的所有文章(包括草稿及未到发布时间的文章)，author为空时返回所有作者的文章，按照更新时间从新到旧排序
+func GetAuthorList(author string, page int, size int) ([]*Post, int, error) {
+	if author == "" {
+		return getList("1=1", g.Slice{}, "updated_at DESC, id DESC", page, size)
+	}
+	return getList("author=?", g.Slice{author}, "updated_at DESC, id DESC", page, size)
+}
+
+// 根据条件分页查询文章列表及其标签和分类，page从1开始，同时返回文章总数
+func getList(where string, args g.Slice, orderBy string, page int, size int) ([]*Post, int, error) {
+	table, err := model.Table(TABLE)
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := table.Where(where, args...).Count()
+	if err != nil {
+		return nil, 0, err
+	}
+	if page < 1 {
+		page = 1
+	}
+	posts := make([]*Post, 0)
+	if total == 0 {
+		return posts, 0, nil
+	}
+	table, _ = model.Table(TABLE)
+	result, err := table.Where(where, args...).OrderBy(orderBy).ForPage(page, size).Select()
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := result.ToStructs(&posts); err != nil {
+		return nil, 0, err
+	}
+	if err := loadTerms(posts); err != nil {
+		return nil, 0, err
+	}
+	return posts, total, nil
+}
+
+// 创建文章，保存时渲染html内容，返回新文章的ID
+func Create(p *Post) (int, error) {
+	if err := p.prepare(); err != nil {
+		return 0, err
+	}
+	if exists, err := GetBySlug(p.Slug); err != nil {
+		return 0, err
+	} else if exists != nil {
+		return 0, ErrSlugExists
+	}
+	table, err := model.Table(TABLE)
+	if err != nil {
+		return 0, err
+	}
+	p.CreatedAt = p.UpdatedAt
+	data := p.data()
+	data["created_at"] = p.CreatedAt
+	result, err := table.Data(data).Insert()
+	if err != nil {
+		return 0, err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	p.Id = int(id)
+	return p.Id, saveTerms(p)
+}
+
+// 更新文章，保存时重新渲染html内容
+func Update(p *Post) error {
+	if err := p.prepare(); err != nil {
+		return err
+	}
+	if exists, err := GetBySlug(p.Slug); err != nil {
+		return err
+	} else if exists != nil && exists.Id != p.Id {
+		return ErrSlugExists
+	}
+	table, err := model.Table(TABLE)
+	if err != nil {
+		return err
+	}
+	if _, err = table.Data(p.data()).Where("id=?", p.Id).Update(); err != nil {
+		return err
+	}
+	return saveTerms(p)
+}
+
+// 删除文章，同时删除文章的标签及分类
+func Delete(id int) error {
+	table, err := model.Table(TABLE)
+	if err != nil {
+		return err
+	}
+	if _, err = table.Where("id=?", id).Delete(); err != nil {
+		return err
+	}
+	return deleteTerms(id)
+}
+
+// 根据条件查询单篇文章，不存在时返回nil
+func getOne(where string, args ...interface{}) (*Post, error) {
+	table, err := model.Table(TABLE)
+	if err != nil {
+		return nil, err
+	}
+	record, err := table.Where(where, args...).One()
+	if err != nil || record == nil {
+		return nil, err
+	}
+	p := new(Post)
+	if err := record.ToStruct(p); err != nil {
+		return nil, err
+	}
+	return p, loadTerms([]*Post{p})
+}
+
+// 保存之前校验并补全文章数据，渲染html内容
+func (p *Post) prepare() error {
+	p.Slug = strings.ToLower(strings.TrimSpace(p.Slug))
+	if !gregex.IsMatchString(slugPattern, p.Slug) {
+		return ErrInvalidSlug
+	}
+	if p.Status == "" {
+		p.Status = STATUS_DRAFT
+	}
+	if p.Status != STATUS_DRAFT && p.Status != STATUS_PUBLISHED {
+		return ErrInvalidStatus
+	}
+	p.UpdatedAt = time.Now().Unix()
+	if p.Status == STATUS_PUBLISHED && p.PublishAt == 0 {
+		p.PublishAt = p.UpdatedAt
+	}
+	p.Html = lib_document.ParsePostMarkdown(p.Markdown)
+	return nil
+}
+
+// 写入数据表的字段数据(不包含id及created_at)
+func (p *Post) data() g.Map {
+	return g.Map{
+		"slug":       p.Slug,
+		"title":      p.Title,
+		"markdown":   p.Markdown,
+		"html":       p.Html,
+		"status":     p.Status,
+		"author":     p.Author,
+		"publish_at": p.PublishAt,
+		"updated_at": p.UpdatedAt,
+	}
+}
@@ -0,0 +1,156 @@
+package model_post
+
+import (
+	"gf-blog/app/model"
+	"github.com/gogf/gf/g"
+	"github.com/gogf/gf/g/os/gcache"
+	"strings"
+	"time"
+)
+
+const (
+	// 文章标签及分类数据表名称
+	TERM_TABLE = "post_term"
+
+	// 分类法类型
+	TERM_TAG      = "tag"
+	TERM_CATEGORY = "category"
+
+	// 对外可见文章及其标签分类列表的缓存键名
+	publishedTermsCacheKey = "post_published_terms"
+	// 对外可见文章列表的缓存时间(毫秒)，保存文章时清除，定时发布的文章最迟在缓存过期后显示
+	publishedTermsCacheExpire = 60 * 1000
+)
+
+func init() {
+	model.Register(TERM_TABLE, map[string]string{
+		model.DB_SQLITE: `
+CREATE TABLE IF NOT EXISTS post_term (
+    post_id INTEGER      NOT NULL,
+    type    VARCHAR(16)  NOT NULL,
+    name    VARCHAR(128) NOT NULL,
+    PRIMARY KEY (post_id, type, name)
+);
+CREATE INDEX IF NOT EXISTS idx_post_term_name ON post_term (type, name)`,
+		model.DB_MYSQL: `
+CREATE TABLE IF NOT EXISTS post_term (
+    post_id INT UNSIGNED NOT NULL,
+    type    VARCHAR(16)  NOT NULL,
+    name    VARCHAR(128) NOT NULL,
+    PRIMARY KEY (post_id, type, name),
+    KEY idx_name (type, name)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4`,
+	})
+}
+
+// 获得所有对外可见的文章(不包含文章内容)及其标签和分类，按照发布时间从新到旧排序。
+// 结果缓存到文章保存或者删除时，调用端不能修改返回的列表
+func GetPublishedTerms() ([]*Post, error) {
+	if cached := gcache.Get(publishedTermsCacheKey); cached != nil {
+		return cached.([]*Post), nil
+	}
+	posts, err := loadPublishedTerms()
+	if err != nil {
+		return nil, err
+	}
+	gcache.Set(publishedTermsCacheKey, posts, publishedTermsCacheExpire)
+	return posts, nil
+}
+
+// 从数据库查询所有对外可见的文章及其标签和分类
+func loadPublishedTerms() ([]*Post, error) {
+	table, err := model.Table(TABLE)
+	if err != nil {
+		return nil, err
+	}
+	result, err := table.Fields("id,slug,title,author,status,publish_at").
+		Where("status=? AND publish_at<=?", STATUS_PUBLISHED, time.Now().Unix()).
+		OrderBy("publish_at DESC, id DESC").Select()
+	if err != nil {
+		return nil, err
+	}
+	posts := make([]*Post, 0)
+	if err := result.ToStructs(&posts); err != nil {
+		return nil, err
+	}
+	return posts, loadTerms(posts)
+}
+
+// 批量加载文章的标签及分类
+func loadTerms(posts []*Post) error {
+	if len(posts) == 0 {
+		return nil
+	}
+	ids   := make(g.Slice, 0, len(posts))
+	index := make(map[int]*Post, len(posts))
+	for _, p := range posts {
+		p.Tags       = make([]string, 0)
+		p.Categories = make([]string, 0)
+		ids          = append(ids, p.Id)
+		index[p.Id]  = p
+	}
+	// 文章数量较多时分批查询，避免超出数据库的参数数量限制
+	for _, batch := range model.Batches(ids) {
+		table, err := model.Table(TERM_TABLE)
+		if err != nil {
+			return err
+		}
+		result, err := table.Where("post_id IN("+model.Placeholders(len(batch))+")", batch...).OrderBy("post_id, type, name").Select()
+		if err != nil {
+			return err
+		}
+		for _, record := range result {
+			p, ok := index[record["post_id"].Int()]
+			if !ok {
+				continue
+			}
+			switch record["type"].String() {
+			case TERM_TAG:
+				p.Tags = append(p.Tags, record["name"].String())
+			case TERM_CATEGORY:
+				p.Categories = append(p.Categories, record["name"].String())
+			}
+		}
+	}
+	return nil
+}
+
+// 保存文章的标签及分类，覆盖原有数据，完成后清除对外可见文章列表的缓存
+func saveTerms(p *Post) error {
+	defer gcache.Remove(publishedTermsCacheKey)
+	if err := deleteTerms(p.Id); err != nil {
+		return err
+	}
+	list := make(g.List, 0)
+	seen := make(map[string]bool)
+	for typ, names := range map[string][]string{TERM_TAG: p.Tags, TERM_CATEGORY: p.Categories} {
+		for _, name := range names {
+			name = strings.Trim(strings.TrimSpace(name), "/")
+			if name == "" || seen[typ+"\x00"+name] {
+				continue
+			}
+			seen[typ+"\x00"+name] = true
+			list = append(list, g.Map{"post_id": p.Id, "type": typ, "name": name})
+		}
+	}
+	if len(list) == 0 {
+		return nil
+	}
+	table, err := model.Table(TERM_TABLE)
+	if err != nil {
+		return err
+	}
+	_, err = table.Data(list).Insert()
+	return err
+}
+
+// 删除文章的标签及分类，完成后清除对外可见文章列表的缓存
+func deleteTerms(id int) error {
+	defer gcache.Remove(publishedTermsCacheKey)
+	table, err := model.Table(TERM_TABLE)
+	if err != nil {
+		return err
+	}
+	_, err = table.Where("post_id=?", id).Delete()
+	return err
+}
@@ -0,0 +1,278 @@
+package model_taxonomy
+
+import (
+	"gf-blog/app/library/document"
+	"gf-blog/app/model"
+	"gf-blog/app/model/post"
+	"github.com/gogf/gf/g/os/glog"
+	"math"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// 内容类型
+	TYPE_DOCUMENT = "document"
+	TYPE_POST     = "post"
+
+	// 标签云的权重等级数量，权重从1开始
+	cloudLevels = 5
+)
+
+// 设置了标签或者分类的内容(文档或者博客文章)
+type Item struct {
+	Type       string    `json:"type"`       // 内容类型
+	Title      string    `json:"title"`      // 标题
+	Url        string    `json:"url"`        // 访问地址
+	Date       time.Time `json:"date"`       // 发布时间
+	Tags       []string  `json:"tags"`       // 标签列表
+	Categories []string  `json:"categories"` // 分类列表
+}
+
+// 分类法中的一个项目(标签或者分类)
+type Term struct {
+	Name     string  `json:"name"`               // 名称，分类为当前层级的名称
+	Path     string  `json:"path"`               // 完整名称，分类为使用"/"分隔的完整层级路径，标签与名称相同
+	Url      string  `json:"url"`                // 项目页面的访问地址
+	Count    int     `json:"count"`              // 内容数量，分类包含所有下级分类的内容
+	Weight   int     `json:"weight,omitempty"`   // 标签云中的权重(1-5)
+	Children []*Term `json:"children,omitempty"` // 下级分类
+}
+
+// 获得所有设置了标签或者分类的内容，包括当前版本的文档及已发布的博客文章，按照发布时间从新到旧排序
+func GetItems() []*Item {
+	items := make([]*Item, 0)
+	for _, doc := range lib_document.GetTermDocuments() {
+		items = append(items, &Item{
+			Type:       TYPE_DOCUMENT,
+			Title:      doc.Title,
+			Url:        "/" + lib_document.EscapePath(doc.Path),
+			Date:       doc.Date,
+			Tags:       doc.Tags,
+			Categories: doc.Categories,
+		})
+	}
+	posts, err := model_post.GetPublishedTerms()
+	if err != nil && err != model.ErrNoDatabase {
+		glog.Cat("taxonomy").Printfln("load post terms error: %v", err)
+	}
+	for _, p := range posts {
+		if len(p.Tags) == 0 && len(p.Categories) == 0 {
+			continue
+		}
+		items = append(items, &Item{
+			Type:       TYPE_POST,
+			Title:      p.Title,
+			Url:        p.Url(),
+			Date:       time.Unix(p.PublishAt, 0),
+			Tags:       p.Tags,
+			Categories: p.Categories,
+		})
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Date.After(items[j].Date)
+	})
+	return items
+}
+
+// 获得所有标签，按照内容数量从多到少排序，标签名称不区分大小写
+func GetTags() []*Term {
+	tags  := make([]*Term, 0)
+	index := make(map[string]*Term)
+	for _, item := range GetItems() {
+		seen := make(map[string]bool)
+		for _, name := range item.Tags {
+			key := tagKey(name)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			if term, ok := index[key]; ok {
+				term.Count++
+				continue
+			}
+			index[key] = &Term{Name: name, Path: name, Url: TagUrl(name), Count: 1}
+			tags = append(tags, index[key])
+		}
+	}
+	sort.SliceStable(tags, func(i, j int) bool {
+		if tags[i].Count != tags[j].Count {
+			return tags[i].Count > tags[j].Count
+		}
+		return tags[i].Name < tags[j].Name
+	})
+	return tags
+}
+
+// 获得标签云，包含内容数量最多的limit个标签(limit<=0时不限制)，按照名称排序，权重根据内容数量按照对数比例计算
+func GetTagCloud(limit int) []*Term {
+	tags := GetTags()
+	if limit > 0 && len(tags) > limit {
+		tags = tags[:limit]
+	}
+	if len(tags) == 0 {
+		return tags
+	}
+	min, max := tags[len(tags)-1].Count, tags[0].Count
+	for _, tag := range tags {
+		tag.Weight = 1
+		if max > min {
+			ratio := (math.Log(float64(tag.Count)) - math.Log(float64(min))) / (math.Log(float64(max)) - math.Log(float64(min)))
+			tag.Weight = 1 + int(math.Round(ratio*(cloudLevels-1)))
+		}
+	}
+	sort.SliceStable(tags, func(i, j int) bool {
+		return strings.ToLower(tags[i].Name) < strings.ToLower(tags[j].Name)
+	})
+	return tags
+}
+
+// 获得指定标签的内容列表，标签不存在时返回空列表
+func GetTagItems(tag string) []*Item {
+	key   := tagKey(tag)
+	items := make([]*Item, 0)
+	for _, item := range GetItems() {
+		for _, name := range item.Tags {
+			if tagKey(name) == key {
+				items = append(items, item)
+				break
+			}
+		}
+	}
+	return items
+}
+
+// 获得层级分类树，同一层级按照名称排序
+func GetCategories() []*Term {
+	root := &Term{Children: make([]*Term, 0)}
+	for _, item := range GetItems() {
+		// 同一内容在同一个分类中只计算一次
+		counted := make(map[*Term]bool)
+		for _, category := range item.Categories {
+			node := root
+			path := ""
+			for _, name := range splitCategory(category) {
+				if path != "" {
+					path += "/"
+				}
+				path += name
+				var child *Term
+				for _, c := range node.Children {
+					if c.Name == name {
+						child = c
+						break
+					}
+				}
+				if child == nil {
+					child = &Term{Name: name, Path: path, Url: CategoryUrl(path), Children: make([]*Term, 0)}
+					node.Children = append(node.Children, child)
+				}
+				if !counted[child] {
+					counted[child] = true
+					child.Count++
+				}
+				node = child
+			}
+		}
+	}
+	sortCategories(root.Children)
+	return root.Children
+}
+
+// 查找指定完整路径的分类，不存在时返回nil
+func GetCategory(path string) *Term {
+	nodes := GetCategories()
+	var found *Term
+	for _, name := range splitCategory(path) {
+		found = nil
+		for _, node := range nodes {
+			if node.Name == name {
+				found = node
+				break
+			}
+		}
+		if found == nil {
+			return nil
+		}
+		nodes = found.Children
+	}
+	return found
+}
+
+// 获得指定分类(包含所有下级分类)的内容列表
+func GetCategoryItems(path string) []*Item {
+	prefix := strings.Join(splitCategory(path), "/")
+	items  := make([]*Item, 0)
+	if prefix == "" {
+		return items
+	}
+	for _, item := range GetItems() {
+		for _, category := range item.Categories {
+			name := strings.Join(splitCategory(category), "/")
+			if name == prefix || strings.HasPrefix(name, prefix+"/") {
+				items = append(items, item)
+				break
+			}
+		}
+	}
+	return items
+}
+
+// 对内容列表分页，page从1开始
+func Paginate(items []*Item, page int, size int) []*Item {
+	total := len(items)
+	if page < 1 {
+		page = 1
+	}
+	start := (page - 1) * size
+	end   := start + size
+	if start > total {
+		start = total
+	}
+	if end > total {
+		end = total
+	}
+	return items[start:end]
+}
+
+// 标签页面的访问地址
+func TagUrl(name string) string {
+	return "/tags/" + url.PathEscape(name)
+}
+
+// 分类页面的访问地址，path为使用"/"分隔的完整层级路径
+func CategoryUrl(path string) string {
+	names := splitCategory(path)
+	for i, name := range names {
+		names[i] = url.PathEscape(name)
+	}
+	return "/categories/" + strings.Join(names, "/")
+}
+
+// 标签的唯一标识，不区分大小写
+func tagKey(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// 将分类路径拆分为各层级的名称，忽略空的层级
+func splitCategory(path string) []string {
+	names := make([]string, 0)
+	for _, name := range strings.Split(path, "/") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// 递归按照名称对分类树排序
+func sortCategories(nodes []*Term) {
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return nodes[i].Name < nodes[j].Name
+	})
+	for _, node := range nodes {
+		sortCategories(node.Children)
+	}
+}
@@ -0,0 +1,251 @@
+package model_user
+
+import (
+	"errors"
+	"gf-blog/app/library/auth"
+	"gf-blog/app/model"
+	"github.com/gogf/gf/g"
+	"github.com/gogf/gf/g/text/gregex"
+	"golang.org/x/crypto/bcrypt"
+	"strings"
+	"time"
+)
+
+const (
+	// 数据表名称
+	TABLE = "user"
+
+	// 用户名格式，只允许字母、数字、下划线、点及中划线
+	usernamePattern = `^[a-zA-Z0-9_.\-]{3,32}$`
+	// 密码最小长度
+	passwordMinLength = 8
+	// bcrypt最多只使用密码的前72个字节
+	passwordMaxLength = 72
+)
+
+var (
+	// 用户名格式错误
+	ErrInvalidUsername = errors.New("invalid username, 3-32 letters, digits, '_', '.' or '-' are allowed")
+	// 用户名已被其他用户使用
+	ErrUsernameExists = errors.New("username already exists")
+	// 密码长度错误
+	ErrInvalidPassword = errors.New("invalid password, 8-72 characters are required")
+	// 角色错误
+	ErrInvalidRole = errors.New("invalid role")
+	// 用户名或者密码错误
+	ErrInvalidLogin = errors.New("invalid username or password")
+
+	// 用户不存在时用于比对的密码哈希，使登录校验的耗时与用户是否存在无关
+	dummyHash, _ = bcrypt.GenerateFromPassword([]byte("gf-blog"), bcrypt.DefaultCost)
+)
+
+// 用户
+type User struct {
+	Id          int    `gconv:"id"            json:"id"`
+	Username    string `gconv:"username"      json:"username"`    // 登录用户名
+	Password    string `gconv:"password"      json:"-"`           // bcrypt密码哈希
+	Nickname    string `gconv:"nickname"      json:"nickname"`    // 昵称
+	Email       string `gconv:"email"         json:"email"`       // 邮箱
+	Role        string `gconv:"role"          json:"role"`        // 角色
+	CreatedAt   int64  `gconv:"created_at"    json:"createdAt"`   // 创建时间(时间戳)
+	UpdatedAt   int64  `gconv:"updated_at"    json:"updatedAt"`   // 更新时间(时间戳)
+	LastLoginAt int64  `gconv:"last_login_at" json:"lastLoginAt"` // 最后登录时间(时间戳)
+}
+
+func init() {
+	model.Register(TABLE, map[string]string{
+		model.DB_SQLITE: `
+CREATE TABLE IF NOT EXISTS user (
+    id            INTEGER PRIMARY KEY AUTOINCREMENT,
+    username      VARCHAR(32)  NOT NULL UNIQUE,
+    password      VARCHAR(255) NOT NULL,
+    nickname      VARCHAR(64)  NOT NULL DEFAULT '',
+    email         VARCHAR(255) NOT NULL DEFAULT '',
+    role          VARCHAR(16)  NOT NULL DEFAULT 'reader',
+    created_at    INTEGER      NOT NULL DEFAULT 0,
+    updated_at    INTEGER      NOT NULL DEFAULT 0,
+    last_login_at INTEGER      NOT NULL DEFAULT 0
+)`,
+		model.DB_MYSQL: `
+CREATE TABLE IF NOT EXISTS user (
+    id            INT UNSIGNED NOT NULL AUTO_INCREMENT,
+    username      VARCHAR(32)  NOT NULL,
+    password      VARCHAR(255) NOT NULL,
+    nickname      VARCHAR(64)  NOT NULL DEFAULT '',
+    email         VARCHAR(255) NOT NULL DEFAULT '',
+    role          VARCHAR(16)  NOT NULL DEFAULT 'reader',
+    created_at    INT          NOT NULL DEFAULT 0,
+    updated_at    INT          NOT NULL DEFAULT 0,
+    last_login_at INT          NOT NULL DEFAULT 0,
+    PRIMARY KEY (id),
+    UNIQUE KEY uk_username (username)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4`,
+	})
+}
+
+// 转换为保存在session中的登录用户信息
+func (u *User) AuthUser() *lib_auth.User {
+	return &lib_auth.User{
+		Id:       u.Id,
+		Username: u.Username,
+		Nickname: u.Nickname,
+		Email:    u.Email,
+		Role:     u.Role,
+	}
+}
+
+// 根据ID获得用户，不存在时返回nil
+func GetById(id int) (*User, error) {
+	return getOne("id=?", id)
+}
+
+// 根据用户名获得用户，不存在时返回nil
+func GetByUsername(username string) (*User, error) {
+	return getOne("username=?", strings.TrimSpace(username))
+}
+
+// 创建用户，password为明文密码，返回新用户的ID
+func Create(u *User, password string) (int, error) {
+	if err := u.prepare(); err != nil {
+		return 0, err
+	}
+	if err := u.setPassword(password); err != nil {
+		return 0, err
+	}
+	if exists, err := GetByUsername(u.Username); err != nil {
+		return 0, err
+	} else if exists != nil {
+		return 0, ErrUsernameExists
+	}
+	table, err := model.Table(TABLE)
+	if err != nil {
+		return 0, err
+	}
+	u.CreatedAt = u.UpdatedAt
+	data := u.data()
+	data["password"]   = u.Password
+	data["created_at"] = u.CreatedAt
+	result, err := table.Data(data).Insert()
+	if err != nil {
+		return 0, err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	u.Id = int(id)
+	return u.Id, nil
+}
+
+// 更新用户的昵称、邮箱及角色，用户名及密码不在此更新
+func Update(u *User) error {
+	if err := u.prepare(); err != nil {
+		return err
+	}
+	table, err := model.Table(TABLE)
+	if err != nil {
+		return err
+	}
+	_, err = table.Data(u.data()).Where("id=?", u.Id).Update()
+	return err
+}
+
+// 修改用户密码，password为明文密码
+func SetPassword(id int, password string) error {
+	u := &User{Id: id}
+	if err := u.setPassword(password); err != nil {
+		return err
+	}
+	table, err := model.Table(TABLE)
+	if err != nil {
+		return err
+	}
+	_, err = table.Data(g.Map{
+		"password":   u.Password,
+		"updated_at": time.Now().Unix(),
+	}).Where("id=?", id).Update()
+	return err
+}
+
+// 校验用户名及密码，成功时返回用户并记录登录时间，失败时返回ErrInvalidLogin
+func Authenticate(username string, password string) (*User, error) {
+	u, err := GetByUsername(username)
+	if err != nil {
+		return nil, err
+	}
+	if u == nil {
+		bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+		return nil, ErrInvalidLogin
+	}
+	if bcrypt.CompareHashAndPassword([]byte(u.Password), []byte(password)) != nil {
+		return nil, ErrInvalidLogin
+	}
+	table, err := model.Table(TABLE)
+	if err != nil {
+		return nil, err
+	}
+	u.LastLoginAt = time.Now().Unix()
+	if _, err = table.Data(g.Map{"last_login_at": u.LastLoginAt}).Where("id=?", u.Id).Update(); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// 根据条件查询单个用户，不存在时返回nil
+func getOne(where string, args ...interface{}) (*User, error) {
+	table, err := model.Table(TABLE)
+	if err != nil {
+		return nil, err
+	}
+	record, err := table.Where(where, args...).One()
+	if err != nil || record == nil {
+		return nil, err
+	}
+	u := new(User)
+	if err := record.ToStruct(u); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// 保存之前校验并补全用户数据
+func (u *User) prepare() error {
+	u.Username = strings.TrimSpace(u.Username)
+	if !gregex.IsMatchString(usernamePattern, u.Username) {
+		return ErrInvalidUsername
+	}
+	if u.Role == "" {
+		u.Role = lib_auth.ROLE_READER
+	}
+	if !lib_auth.IsRole(u.Role) {
+		return ErrInvalidRole
+	}
+	u.Nickname  = strings.TrimSpace(u.Nickname)
+	u.Email     = strings.TrimSpace(u.Email)
+	u.UpdatedAt = time.Now().Unix()
+	return nil
+}
+
+// 校验明文密码并生成bcrypt密码哈希
+func (u *User) setPassword(password string) error {
+	if len(password) < passwordMinLength || len(password) > passwordMaxLength {
+		return ErrInvalidPassword
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	u.Password = string(hash)
+	return nil
+}
+
+// 写入数据表的字段数据(不包含id、password、created_at及last_login_at)
+func (u *User) data() g.Map {
+	return g.Map{
+		"username":   u.Username,
+		"nickname":   u.Nickname,
+		"email":      u.Email,
+		"role":       u.Role,
+		"updated_at": u.UpdatedAt,
+	}
+}
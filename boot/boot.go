@@ -1,9 +1,58 @@
 package boot
 
-import "github.com/gogf/gf/g"
+import (
+    "gf-blog/app/library/document"
+    "gf-blog/app/library/livereload"
+    "github.com/gogf/gf/g"
+    "github.com/gogf/gf/g/os/glog"
+)
 
 // 用于应用初始化。
 func init() {
-    g.Server().SetPort(8199)
+    c := g.Config()
+    v := g.View()
+    s := g.Server()
+
+    // 配置对象及视图对象配置
+    c.AddPath("config")
+    v.AddPath("template")
+    v.Assign("devMode", c.GetBool("setting.devmode"))
+    v.BindFunc("urlpath", lib_document.EscapePath)
+    if theme := c.GetString("highlight.theme"); theme != "" {
+        v.Assign("highlightTheme", theme)
+    } else {
+        v.Assign("highlightTheme", "github")
+    }
+
+    // glog配置
+    logpath := c.GetString("setting.logpath")
+    if logpath != "" {
+        glog.SetPath(logpath)
+        s.SetLogPath(logpath)
+    }
+    glog.SetStdPrint(true)
+
+    // Web Server配置
+    s.SetServerRoot("public")
+    s.SetErrorLogEnabled(true)
+    s.SetAccessLogEnabled(true)
+    s.SetPort(8199)
+
+    // 异步检出多版本文档的worktree，并构建各版本的文档全文检索索引
+    go func() {
+        lib_document.SetupVersions()
+        lib_document.BuildSearchIndex()
+    }()
+
+    // 本地编写文档时监控文档目录，实时刷新文档缓存
+    if c.GetBool("document.watch") {
+        if err := lib_document.Watch(); err != nil {
+            glog.Error("document watch failed:", err)
+        }
+    }
+    // 开发模式下文档变更时通知浏览器刷新页面
+    if c.GetBool("setting.devmode") {
+        lib_document.AddChangeListener(lib_livereload.Notify)
+    }
 }
 
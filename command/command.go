@@ -0,0 +1,15 @@
+package command
+
+import (
+    "gf-blog/app/command/check"
+    "gf-blog/app/command/export"
+    "gf-blog/app/command/user"
+    "github.com/gogf/gf/g/os/gcmd"
+)
+
+// 统一命令行命令注册，例如: gf-blog export --out=dist
+func init() {
+    gcmd.BindHandle("export", cmd_export.Run)
+    gcmd.BindHandle("check",  cmd_check.Run)
+    gcmd.BindHandle("user",   cmd_user.Run)
+}
@@ -2,6 +2,8 @@ module gf-blog
 
 require (
 	github.com/gogf/gf v1.5.23
+	github.com/mattn/go-sqlite3 v2.0.3+incompatible
 	github.com/russross/blackfriday v2.0.0+incompatible // indirect
 	github.com/shurcooL/sanitized_anchor_name v1.0.0 // indirect
+	golang.org/x/crypto v0.9.0
 )
@@ -2,10 +2,20 @@ package main
 
 import (
 	_ "gf-blog/boot"
+	_ "gf-blog/command"
 	_ "gf-blog/router"
 	"github.com/gogf/gf/g"
+	"github.com/gogf/gf/g/os/gcmd"
+	"github.com/gogf/gf/g/os/glog"
 )
 
 func main() {
+	// 带有命令参数时执行对应的命令行命令，否则启动Web Server
+	if gcmd.Value.Get(1) != "" {
+		if err := gcmd.AutoRun(); err != nil {
+			glog.Fatal(err)
+		}
+		return
+	}
 	g.Server().Run()
 }
@@ -0,0 +1,69 @@
+// 文档编辑页面：输入时实时预览，通过ajax保存(Ctrl+S)，文档在加载后被其他人修改时提示冲突
+(function () {
+    var form    = document.getElementById("editor-form");
+    var preview = document.getElementById("editor-preview");
+    var status  = document.getElementById("editor-status");
+    var source  = form.elements["content"];
+    var timer   = null;
+
+    function post(url, data, callback) {
+        var xhr = new XMLHttpRequest();
+        xhr.open("POST", url);
+        xhr.setRequestHeader("X-Requested-With", "XMLHttpRequest");
+        xhr.setRequestHeader("X-CSRF-Token", form.elements["_csrf"].value);
+        xhr.onload = function () {
+            var result = null;
+            try {
+                result = JSON.parse(xhr.responseText);
+            } catch (e) {
+                result = {code: 0, msg: xhr.statusText, data: null};
+            }
+            callback(xhr.status, result);
+        };
+        xhr.send(data);
+    }
+
+    function renderPreview() {
+        var data = new FormData();
+        data.append("content", source.value);
+        post("/editor/preview", data, function (code, result) {
+            if (result.code === 1) {
+                preview.innerHTML = result.data.html;
+            }
+        });
+    }
+
+    function save() {
+        status.textContent = "保存中...";
+        post("/editor/save", new FormData(form), function (code, result) {
+            if (code === 409) {
+                status.textContent = "保存失败: 文档已被其他人修改，请复制当前内容后重新加载页面";
+                return;
+            }
+            if (result.code !== 1) {
+                status.textContent = "保存失败: " + result.msg;
+                return;
+            }
+            // 后续保存以本次保存的内容为基准
+            form.elements["hash"].value = result.data.hash;
+            form.elements["message"].value = "";
+            status.textContent = result.msg ? "已保存，但提交版本库失败: " + result.msg : "已保存";
+        });
+    }
+
+    source.addEventListener("input", function () {
+        clearTimeout(timer);
+        timer = setTimeout(renderPreview, 300);
+    });
+    form.addEventListener("submit", function (e) {
+        e.preventDefault();
+        save();
+    });
+    document.addEventListener("keydown", function (e) {
+        if ((e.ctrlKey || e.metaKey) && e.key === "s") {
+            e.preventDefault();
+            save();
+        }
+    });
+    renderPreview();
+})();
@@ -0,0 +1,29 @@
+// 开发模式下的文档实时刷新：当前文档变更时自动刷新页面
+(function () {
+    var path     = window.location.pathname;
+    var protocol = window.location.protocol === "https:" ? "wss://" : "ws://";
+    var url      = protocol + window.location.host + "/livereload?path=" + encodeURIComponent(path);
+    var retry    = 0;
+
+    function connect() {
+        var ws = new WebSocket(url);
+        ws.onopen = function () {
+            // 服务端重启后重新连接成功，刷新页面以加载最新内容
+            if (retry > 0) {
+                window.location.reload();
+            }
+        };
+        ws.onmessage = function (e) {
+            var message = JSON.parse(e.data);
+            if (message.event === "changed") {
+                window.location.reload();
+            }
+        };
+        ws.onclose = function () {
+            retry++;
+            setTimeout(connect, Math.min(1000 * retry, 5000));
+        };
+    }
+
+    connect();
+})();
@@ -0,0 +1,103 @@
+// 静态站点的客户端文档检索：加载导出的检索索引文件，根据查询关键字进行检索并渲染结果
+(function () {
+    var container = document.querySelector(".search[data-index]");
+    if (!container) {
+        return;
+    }
+    var script  = document.currentScript || document.querySelector('script[src$="/resource/js/search.js"]');
+    var base    = script.src.replace(/\/resource\/js\/search\.js.*$/, "").replace(/^https?:\/\/[^\/]+/, "");
+    var params  = new URLSearchParams(window.location.search);
+    var key     = (params.get("key") || "").trim();
+    var version = params.get("version") || "";
+    var url     = container.getAttribute("data-index");
+    if (version) {
+        url = base + "/" + version + "/search-index.json";
+    }
+    container.querySelector('input[name="key"]').value = key;
+    if (version) {
+        container.querySelector("form").insertAdjacentHTML("beforeend",
+            '<input type="hidden" name="version" value="' + escapeHtml(version) + '">');
+    }
+    if (key === "") {
+        return;
+    }
+
+    fetch(url).then(function (response) {
+        return response.json();
+    }).then(function (index) {
+        render(search(index, key));
+    });
+
+    // 多个关键字之间为AND关系，优先使用导出的词项倒排列表计算BM25得分，
+    // 未命中词项(例如中文分词不一致)时使用文档文本的子串匹配
+    function search(index, key) {
+        var words   = key.toLowerCase().split(/\s+/);
+        var scores  = {};
+        var matched = null;
+        words.forEach(function (word) {
+            var docs     = {};
+            var postings = index.terms[word] || [];
+            var idf      = Math.log(1 + (index.ids.length - postings.length + 0.5) / (postings.length + 0.5));
+            postings.forEach(function (item) {
+                var tf   = item[1];
+                var norm = 1 - index.b + index.b * index.lengths[item[0]] / (index.avgLen || 1);
+                docs[item[0]] = idf * tf * (index.k1 + 1) / (tf + index.k1 * norm);
+            });
+            index.ids.forEach(function (id, i) {
+                if (docs[i] !== undefined) {
+                    return;
+                }
+                var text  = (index.titles[i] + "\n" + index.texts[i]).toLowerCase();
+                var count = text.split(word).length - 1;
+                if (count > 0) {
+                    docs[i] = count / (count + index.k1);
+                }
+            });
+            var next = {};
+            Object.keys(docs).forEach(function (i) {
+                if (matched === null || matched[i]) {
+                    next[i] = true;
+                    scores[i] = (scores[i] || 0) + docs[i];
+                }
+            });
+            matched = next;
+        });
+        return Object.keys(matched || {}).sort(function (a, b) {
+            return scores[b] - scores[a];
+        }).map(function (i) {
+            return {
+                path:    index.ids[i],
+                title:   index.titles[i] || index.ids[i],
+                snippet: snippet(index.texts[i], words[0])
+            };
+        });
+    }
+
+    // 截取第一个关键字所在位置附近的文本片段
+    function snippet(text, word) {
+        var pos   = text.toLowerCase().indexOf(word);
+        var start = Math.max(0, pos - 30);
+        var part  = text.substr(start, 120).replace(/\n/g, " ");
+        return (start > 0 ? "…" : "") + escapeHtml(part).split(escapeHtml(word)).join("<mark>" + escapeHtml(word) + "</mark>");
+    }
+
+    function render(hits) {
+        var html = '<p class="search-total">共找到 ' + hits.length + ' 个相关文档</p><ul class="search-hits">';
+        hits.forEach(function (hit) {
+            html += '<li class="search-hit"><a class="search-hit-title" href="' + base + escapeHtml(encodePath(hit.path)) + '.html">'
+                + escapeHtml(hit.title) + '</a><p class="search-hit-snippet">' + hit.snippet + '</p></li>';
+        });
+        container.querySelector(".search-hits").outerHTML = html + "</ul>";
+    }
+
+    // 对文档路径的每一级分别进行URL编码，保留分隔符"/"
+    function encodePath(path) {
+        return String(path).split("/").map(encodeURIComponent).join("/");
+    }
+
+    function escapeHtml(s) {
+        return String(s).replace(/[&<>"']/g, function (c) {
+            return {"&": "&amp;", "<": "&lt;", ">": "&gt;", '"': "&#34;", "'": "&#39;"}[c];
+        });
+    }
+})();
@@ -1,11 +1,61 @@
 package router
 
 import (
+    "gf-blog/app/controller/comment"
+    "gf-blog/app/controller/document"
     "gf-blog/app/controller/hello"
+    "gf-blog/app/controller/post"
+    "gf-blog/app/controller/taxonomy"
+    "gf-blog/app/controller/user"
+    "gf-blog/app/library/auth"
     "github.com/gogf/gf/g"
+    "github.com/gogf/gf/g/net/ghttp"
 )
 
 // 统一路由注册.
 func init() {
-    g.Server().BindHandler("/", ctl_hello.Handler)
+    g.Server().BindHandler("/hello",                        ctl_hello.Handler)
+    g.Server().BindHandler("POST:/hook",                    ctl_document.Hook)
+    g.Server().BindHandler("/search",                       ctl_document.Search)
+    g.Server().BindHandler("/menu",                         ctl_document.Menu)
+    g.Server().BindHandler("/livereload",                   ctl_document.LiveReload)
+    g.Server().BindHandler("/rss.xml",                      ctl_document.Rss)
+    g.Server().BindHandler("/atom.xml",                     ctl_document.Atom)
+    g.Server().BindHandler("/rss/*section",                 ctl_document.Rss)
+    g.Server().BindHandler("/atom/*section",                ctl_document.Atom)
+    g.Server().BindHandler("/sitemap.xml",                  ctl_document.Sitemap)
+    g.Server().BindHandler("/sitemap-{page}.xml",           ctl_document.SitemapPage)
+    g.Server().BindHandler("/robots.txt",                   ctl_document.Robots)
+    g.Server().BindHandler("/history/*path",                ctl_document.History)
+    g.Server().BindHandler("/revision/:hash/*path",         ctl_document.Revision)
+    g.Server().BindHandler("/admin/check",                  ctl_document.Check)
+    g.Server().BindHandler("/diff/*path",                   ctl_document.Diff)
+    g.Server().BindHandler("/blog",                         ctl_post.List)
+    g.Server().BindHandler("/blog/:slug",                   ctl_post.Detail)
+    g.Server().BindHandler("/tags",                         ctl_taxonomy.Tags)
+    g.Server().BindHandler("/tags/:tag",                    ctl_taxonomy.Tag)
+    g.Server().BindHandler("/categories",                   ctl_taxonomy.Categories)
+    g.Server().BindHandler("/categories/*path",             ctl_taxonomy.Category)
+    g.Server().BindHandler("GET:/comments",                 ctl_comment.List)
+    g.Server().BindHandler("POST:/comments",                ctl_comment.Post)
+    g.Server().BindHandler("/comments/count",               ctl_comment.Count)
+    g.Server().BindHandler("/admin/comments",               ctl_comment.AdminList)
+    g.Server().BindHandler("POST:/admin/comments/moderate", ctl_comment.AdminModerate)
+    g.Server().BindHandler("/login",                        ctl_user.Login)
+    g.Server().BindHandler("/logout",                       ctl_user.Logout)
+    g.Server().BindHandler("/user/me",                      ctl_user.Me)
+    g.Server().BindHandler("GET:/editor/*path",             ctl_document.Editor)
+    g.Server().BindHandler("POST:/editor/preview",          ctl_document.EditorPreview)
+    g.Server().BindHandler("POST:/editor/save",             ctl_document.EditorSave)
+    g.Server().BindHandler("GET:/author/posts",             ctl_post.AuthorList)
+    g.Server().BindHandler("POST:/author/posts/save",       ctl_post.AuthorSave)
+    g.Server().BindHandler("POST:/author/posts/delete",     ctl_post.AuthorDelete)
+    g.Server().BindHandler("/*path",                        ctl_document.Index)
+
+    // 管理接口需要登录并拥有管理权限
+    g.Server().BindHookHandler("/admin/*any",  ghttp.HOOK_BEFORE_SERVE, lib_auth.Guard(lib_auth.PERM_MANAGE))
+    // 文档编辑需要登录并拥有编辑权限
+    g.Server().BindHookHandler("/editor/*any", ghttp.HOOK_BEFORE_SERVE, lib_auth.Guard(lib_auth.PERM_EDIT_DOC))
+    // 博客文章撰写需要登录并拥有撰写文章权限
+    g.Server().BindHookHandler("/author/*any", ghttp.HOOK_BEFORE_SERVE, lib_auth.Guard(lib_auth.PERM_WRITE_POST))
 }